@@ -9,9 +9,11 @@ require (
 	github.com/chai2010/gettext-go v0.0.0-20170215093142-bf70f2a70fb1 // indirect
 	github.com/exponent-io/jsonpath v0.0.0-20151013193312-d6023ce2651d // indirect
 	github.com/fatih/camelcase v1.0.0 // indirect
+	github.com/ghodss/yaml v1.0.0
 	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b
 	github.com/liggitt/tabwriter v0.0.0-20181228230101-89fcab3d43de // indirect
 	github.com/mitchellh/go-wordwrap v1.0.0 // indirect
+	github.com/robfig/cron v1.2.0
 	github.com/spf13/cobra v0.0.5
 	github.com/xlab/handysort v0.0.0-20150421192137-fb3537ed64a1 // indirect
 	k8s.io/api v0.0.0-20190503110853-61630f889b3c
@@ -21,8 +23,10 @@ require (
 	k8s.io/component-base v0.0.0-20190314000054-4a91899592f4
 	k8s.io/kubernetes v1.14.0
 	kmodules.xyz/client-go v0.0.0-20190808141354-bbb9e14f60ab
+	kmodules.xyz/custom-resources v0.0.0-20190808144301-114abf10dfe2
 	kmodules.xyz/monitoring-agent-api v0.0.0-20190808150221-601a4005b7f7
 	kmodules.xyz/objectstore-api v0.0.0-20190808153322-733e8798e8de
+	kmodules.xyz/offshoot-api v0.0.0-20190808152534-e3dc715f844b
 	kubedb.dev/apimachinery v0.13.0-rc.0
 	vbom.ml/util v0.0.0-20180919145318-efcd4e0f9787 // indirect
 )
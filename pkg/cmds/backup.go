@@ -0,0 +1,317 @@
+package cmds
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	batch "k8s.io/api/batch/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+	store "kmodules.xyz/objectstore-api/api/v1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/databases"
+)
+
+var (
+	backupLong = templates.LongDesc(`
+		Take an on-demand backup of a database.
+
+		A Snapshot of the database is created and followed until it succeeds or fails. The backend,
+		storage secret, storage type and pod template of the backup schedule of the database are used,
+		unless overridden by flags. Databases without a backup schedule require --backend and --storage-secret.
+
+		Backends are given as URLs: s3://BUCKET/PREFIX, gs://BUCKET/PREFIX, azure://CONTAINER/PREFIX,
+		swift://CONTAINER/PREFIX or b2://BUCKET/PREFIX.`)
+
+	backupExample = templates.Examples(`
+		# Back up a postgres to the backend of its backup schedule
+		kubedb backup pg/postgres-demo
+
+		# Back up a mongodb to a GCS bucket
+		kubedb backup mg/mongodb-demo --backend=gs://kubedb-backups/demo --storage-secret=gcs-secret
+
+		# Back up a mysql to a minio server, without waiting for the snapshot to complete
+		kubedb backup my/mysql-demo --backend=s3://backups --s3-endpoint=http://minio.storage:9000 --storage-secret=minio-secret --wait=false`)
+)
+
+type BackupOptions struct {
+	Namespace string
+
+	SnapshotName  string
+	Backend       string
+	S3Endpoint    string
+	StorageSecret string
+	StorageType   string
+
+	Wait    bool
+	Timeout time.Duration
+
+	Client       kubernetes.Interface
+	KubedbClient cs.KubedbV1alpha1Interface
+	Result       *resource.Result
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdBackup(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &BackupOptions{
+		Wait:      true,
+		Timeout:   30 * time.Minute,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "backup (TYPE NAME | TYPE/NAME)",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Take an on-demand snapshot of a database"),
+		Long:                  backupLong,
+		Example:               backupExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringVar(&o.SnapshotName, "name", o.SnapshotName, "Name of the snapshot. Defaults to the name of the database followed by the current time.")
+	cmd.Flags().StringVar(&o.Backend, "backend", o.Backend, "URL of the backend to store the snapshot in, i.e. s3://BUCKET/PREFIX.")
+	cmd.Flags().StringVar(&o.S3Endpoint, "s3-endpoint", o.S3Endpoint, "Endpoint of an S3 compatible backend.")
+	cmd.Flags().StringVar(&o.StorageSecret, "storage-secret", o.StorageSecret, "Name of the secret holding the credentials of the backend.")
+	cmd.Flags().StringVar(&o.StorageType, "storage-type", o.StorageType, "Storage type of the backup job. One of: Durable|Ephemeral.")
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "If true, follow the snapshot until it succeeds or fails.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait, zero means wait forever.")
+	return cmd
+}
+
+func (o *BackupOptions) Complete(f cmdutil.Factory, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("You must specify the database to back up.")
+	}
+	if o.StorageType != "" && o.StorageType != string(api.StorageTypeDurable) && o.StorageType != string(api.StorageTypeEphemeral) {
+		return fmt.Errorf("unsupported storage type %q, allowed types are: %s, %s", o.StorageType, api.StorageTypeDurable, api.StorageTypeEphemeral)
+	}
+	if o.S3Endpoint != "" && !strings.HasPrefix(o.Backend, "s3://") {
+		return fmt.Errorf("--s3-endpoint requires an s3:// --backend")
+	}
+
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.Result = f.NewBuilder().
+		Unstructured().
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		ResourceTypeOrNameArgs(false, args...).
+		SingleResourceType().
+		Flatten().
+		Do()
+	if err = o.Result.Err(); err != nil {
+		return err
+	}
+
+	o.Client, err = f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.KubedbClient, err = newKubedbClient(f)
+	return err
+}
+
+func (o *BackupOptions) Run() error {
+	infos, err := o.Result.Infos()
+	if err != nil {
+		return err
+	}
+	if len(infos) != 1 {
+		return fmt.Errorf("You must specify exactly one database to back up, found %d.", len(infos))
+	}
+	info := infos[0]
+	gvk := info.Mapping.GroupVersionKind
+	if gvk.Group != api.SchemeGroupVersion.Group || !databases.Kinds[gvk.Kind].Snapshots {
+		return fmt.Errorf("%s %q can not be backed up, only %s databases support snapshots", gvk.Kind, info.Name, strings.Join(databases.SnapshotKinds(), ", "))
+	}
+	obj, ok := info.Object.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", info.Object)
+	}
+
+	snapshot, err := o.newSnapshot(gvk.Kind, obj)
+	if err != nil {
+		return err
+	}
+	snapshot, err = o.KubedbClient.Snapshots(snapshot.Namespace).Create(snapshot)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "snapshot.%s %q created\n", api.SchemeGroupVersion.Group, snapshot.Name)
+
+	if !o.Wait {
+		return nil
+	}
+	return o.followSnapshot(snapshot)
+}
+
+// newSnapshot builds the snapshot of db from its backup schedule and the given flags.
+func (o *BackupOptions) newSnapshot(kind string, db *unstructured.Unstructured) (*api.Snapshot, error) {
+	var schedule api.BackupScheduleSpec
+	if content, found, err := unstructured.NestedMap(db.Object, "spec", "backupSchedule"); err != nil {
+		return nil, err
+	} else if found {
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, &schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	name := o.SnapshotName
+	if name == "" {
+		name = fmt.Sprintf("%s-%s", db.GetName(), time.Now().UTC().Format("20060102-150405"))
+	}
+	snapshot := &api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: db.GetNamespace(),
+			Labels: map[string]string{
+				api.LabelDatabaseKind: kind,
+				api.LabelDatabaseName: db.GetName(),
+			},
+		},
+		Spec: api.SnapshotSpec{
+			DatabaseName:       db.GetName(),
+			Backend:            schedule.Backend,
+			StorageType:        schedule.StorageType,
+			PodTemplate:        schedule.PodTemplate,
+			PodVolumeClaimSpec: schedule.PodVolumeClaimSpec,
+		},
+	}
+
+	if o.Backend != "" {
+		backend, err := parseBackend(o.Backend, o.S3Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		backend.StorageSecretName = snapshot.Spec.StorageSecretName
+		snapshot.Spec.Backend = *backend
+	}
+	if o.StorageSecret != "" {
+		snapshot.Spec.StorageSecretName = o.StorageSecret
+	}
+	if o.StorageType != "" {
+		storageType := api.StorageType(o.StorageType)
+		snapshot.Spec.StorageType = &storageType
+	}
+
+	if !hasBackend(snapshot.Spec.Backend) {
+		return nil, fmt.Errorf("%s %q has no backup schedule, specify --backend and --storage-secret", kind, db.GetName())
+	}
+	if snapshot.Spec.StorageSecretName == "" && snapshot.Spec.Local == nil {
+		return nil, fmt.Errorf("no storage secret found for %s %q, specify --storage-secret", kind, db.GetName())
+	}
+	return snapshot, nil
+}
+
+// followSnapshot prints the phase of the snapshot and the progress of its backup job until the snapshot
+// completes. An error with the reason reported by the operator is returned if the snapshot fails.
+func (o *BackupOptions) followSnapshot(snapshot *api.Snapshot) error {
+	var phase api.SnapshotPhase
+	var progress string
+	err := poll(o.Timeout, func() (bool, error) {
+		s, err := o.KubedbClient.Snapshots(snapshot.Namespace).Get(snapshot.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if s.Status.Phase != phase {
+			phase = s.Status.Phase
+			fmt.Fprintf(o.Out, "snapshot %q is %s\n", s.Name, orUnknown(string(phase)))
+		}
+
+		// only the phase is reported until the backup job is found, or if it can not be looked up
+		job, err := o.snapshotJob(s)
+		if err != nil && !kerr.IsForbidden(err) {
+			return false, err
+		}
+		if job != nil {
+			p := fmt.Sprintf("active: %d, succeeded: %d, failed: %d", job.Status.Active, job.Status.Succeeded, job.Status.Failed)
+			if p != progress {
+				progress = p
+				fmt.Fprintf(o.Out, "job %q %s\n", job.Name, progress)
+			}
+		}
+
+		switch s.Status.Phase {
+		case api.SnapshotPhaseSucceeded:
+			return true, nil
+		case api.SnapshotPhaseFailed:
+			return false, fmt.Errorf("snapshot %q failed: %s", s.Name, orUnknown(s.Status.Reason))
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for snapshot %q to complete", snapshot.Name)
+	}
+	return err
+}
+
+// snapshotJob returns the backup job the operator runs for the snapshot s, nil if there is none.
+func (o *BackupOptions) snapshotJob(s *api.Snapshot) (*batch.Job, error) {
+	selector := databases.SnapshotJobSelector(s)
+	if selector == nil {
+		return nil, nil
+	}
+	jobs, err := o.Client.BatchV1().Jobs(s.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs.Items {
+		if databases.IsSnapshotJob(&jobs.Items[i], s) {
+			return &jobs.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func hasBackend(b store.Backend) bool {
+	return b.Local != nil || b.S3 != nil || b.GCS != nil || b.Azure != nil || b.Swift != nil || b.B2 != nil || b.Rest != nil
+}
+
+// parseBackend parses a backend URL, i.e. s3://BUCKET/PREFIX.
+func parseBackend(backend, s3Endpoint string) (*store.Backend, error) {
+	u, err := url.Parse(backend)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "local" {
+		return nil, fmt.Errorf("local backend %q can not be given as a URL, add it to the backup schedule of the database", backend)
+	}
+	bucket := u.Host
+	prefix := strings.Trim(u.Path, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("backend %q has no bucket or container", backend)
+	}
+
+	switch u.Scheme {
+	case "s3":
+		return &store.Backend{S3: &store.S3Spec{Endpoint: s3Endpoint, Bucket: bucket, Prefix: prefix}}, nil
+	case "gs", "gcs":
+		return &store.Backend{GCS: &store.GCSSpec{Bucket: bucket, Prefix: prefix}}, nil
+	case "azure":
+		return &store.Backend{Azure: &store.AzureSpec{Container: bucket, Prefix: prefix}}, nil
+	case "swift":
+		return &store.Backend{Swift: &store.SwiftSpec{Container: bucket, Prefix: prefix}}, nil
+	case "b2":
+		return &store.Backend{B2: &store.B2Spec{Bucket: bucket, Prefix: prefix}}, nil
+	}
+	return nil, fmt.Errorf("unsupported backend %q, allowed schemes are: s3, gs, azure, swift, b2", backend)
+}
@@ -0,0 +1,311 @@
+package cmds
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	store "kmodules.xyz/objectstore-api/api/v1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/internal/fakeapi"
+)
+
+func TestParseBackend(t *testing.T) {
+	cases := []struct {
+		backend    string
+		s3Endpoint string
+		want       *store.Backend
+		wantErr    string
+	}{
+		{
+			backend:    "s3://backups/demo/pg",
+			s3Endpoint: "http://minio.storage:9000",
+			want:       &store.Backend{S3: &store.S3Spec{Endpoint: "http://minio.storage:9000", Bucket: "backups", Prefix: "demo/pg"}},
+		},
+		{backend: "s3://backups", want: &store.Backend{S3: &store.S3Spec{Bucket: "backups"}}},
+		{backend: "gs://backups/demo/", want: &store.Backend{GCS: &store.GCSSpec{Bucket: "backups", Prefix: "demo"}}},
+		{backend: "gcs://backups/demo", want: &store.Backend{GCS: &store.GCSSpec{Bucket: "backups", Prefix: "demo"}}},
+		{backend: "azure://backups/demo", want: &store.Backend{Azure: &store.AzureSpec{Container: "backups", Prefix: "demo"}}},
+		{backend: "swift://backups/demo", want: &store.Backend{Swift: &store.SwiftSpec{Container: "backups", Prefix: "demo"}}},
+		{backend: "b2://backups/demo", want: &store.Backend{B2: &store.B2Spec{Bucket: "backups", Prefix: "demo"}}},
+		{backend: "local:///var/backups", wantErr: `local backend "local:///var/backups" can not be given as a URL, add it to the backup schedule of the database`},
+		{backend: "s3:///demo", wantErr: `backend "s3:///demo" has no bucket or container`},
+		{backend: "ftp://backups/demo", wantErr: `unsupported backend "ftp://backups/demo", allowed schemes are: s3, gs, azure, swift, b2`},
+		{backend: "backups/demo", wantErr: `backend "backups/demo" has no bucket or container`},
+	}
+	for _, c := range cases {
+		t.Run(c.backend, func(t *testing.T) {
+			got, err := parseBackend(c.backend, c.s3Endpoint)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("parseBackend() error = %v, want %s", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBackend() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("parseBackend() = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func testDatabase(backupSchedule map[string]interface{}) *unstructured.Unstructured {
+	db := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": api.SchemeGroupVersion.String(),
+		"kind":       api.ResourceKindPostgres,
+		"metadata":   map[string]interface{}{"name": "pg", "namespace": "demo"},
+		"spec":       map[string]interface{}{},
+	}}
+	if backupSchedule != nil {
+		_ = unstructured.SetNestedMap(db.Object, backupSchedule, "spec", "backupSchedule")
+	}
+	return db
+}
+
+func TestNewSnapshot(t *testing.T) {
+	durable := api.StorageTypeDurable
+	ephemeral := api.StorageTypeEphemeral
+	schedule := map[string]interface{}{
+		"cronExpression":    "@every 6h",
+		"storageSecretName": "gcs-secret",
+		"gcs":               map[string]interface{}{"bucket": "scheduled", "prefix": "pg"},
+		"storageType":       "Durable",
+	}
+	localSchedule := map[string]interface{}{
+		"cronExpression": "@every 6h",
+		"local": map[string]interface{}{
+			"mountPath": "/repo",
+			"hostPath":  map[string]interface{}{"path": "/data/backups"},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		o        BackupOptions
+		schedule map[string]interface{}
+		want     api.SnapshotSpec
+		wantErr  string
+	}{
+		{
+			name:     "backup schedule",
+			o:        BackupOptions{SnapshotName: "pg-snap"},
+			schedule: schedule,
+			want: api.SnapshotSpec{
+				DatabaseName: "pg",
+				Backend:      store.Backend{StorageSecretName: "gcs-secret", GCS: &store.GCSSpec{Bucket: "scheduled", Prefix: "pg"}},
+				StorageType:  &durable,
+			},
+		},
+		{
+			name:     "backend flag keeps the storage secret of the backup schedule",
+			o:        BackupOptions{SnapshotName: "pg-snap", Backend: "s3://adhoc/pg", S3Endpoint: "http://minio:9000"},
+			schedule: schedule,
+			want: api.SnapshotSpec{
+				DatabaseName: "pg",
+				Backend:      store.Backend{StorageSecretName: "gcs-secret", S3: &store.S3Spec{Endpoint: "http://minio:9000", Bucket: "adhoc", Prefix: "pg"}},
+				StorageType:  &durable,
+			},
+		},
+		{
+			name:     "storage flags override the backup schedule",
+			o:        BackupOptions{SnapshotName: "pg-snap", StorageSecret: "other-secret", StorageType: "Ephemeral"},
+			schedule: schedule,
+			want: api.SnapshotSpec{
+				DatabaseName: "pg",
+				Backend:      store.Backend{StorageSecretName: "other-secret", GCS: &store.GCSSpec{Bucket: "scheduled", Prefix: "pg"}},
+				StorageType:  &ephemeral,
+			},
+		},
+		{
+			name: "no backup schedule",
+			o:    BackupOptions{SnapshotName: "pg-snap", Backend: "azure://adhoc/pg", StorageSecret: "azure-secret"},
+			want: api.SnapshotSpec{
+				DatabaseName: "pg",
+				Backend:      store.Backend{StorageSecretName: "azure-secret", Azure: &store.AzureSpec{Container: "adhoc", Prefix: "pg"}},
+			},
+		},
+		{
+			name:     "local backend of the backup schedule needs no storage secret",
+			o:        BackupOptions{SnapshotName: "pg-snap"},
+			schedule: localSchedule,
+			want: api.SnapshotSpec{
+				DatabaseName: "pg",
+				Backend: store.Backend{Local: &store.LocalSpec{
+					MountPath:    "/repo",
+					VolumeSource: core.VolumeSource{HostPath: &core.HostPathVolumeSource{Path: "/data/backups"}},
+				}},
+			},
+		},
+		{
+			name:    "no backend",
+			o:       BackupOptions{SnapshotName: "pg-snap", StorageSecret: "gcs-secret"},
+			wantErr: `Postgres "pg" has no backup schedule, specify --backend and --storage-secret`,
+		},
+		{
+			name:    "no storage secret",
+			o:       BackupOptions{SnapshotName: "pg-snap", Backend: "gs://adhoc/pg"},
+			wantErr: `no storage secret found for Postgres "pg", specify --storage-secret`,
+		},
+		{
+			name:     "invalid backend",
+			o:        BackupOptions{SnapshotName: "pg-snap", Backend: "ftp://adhoc/pg"},
+			schedule: schedule,
+			wantErr:  `unsupported backend "ftp://adhoc/pg", allowed schemes are: s3, gs, azure, swift, b2`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.o.newSnapshot(api.ResourceKindPostgres, testDatabase(c.schedule))
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("newSnapshot() error = %v, want %s", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newSnapshot() error = %v", err)
+			}
+			if got.Name != "pg-snap" || got.Namespace != "demo" {
+				t.Errorf("newSnapshot() = %s/%s, want demo/pg-snap", got.Namespace, got.Name)
+			}
+			wantLabels := map[string]string{api.LabelDatabaseKind: api.ResourceKindPostgres, api.LabelDatabaseName: "pg"}
+			if !reflect.DeepEqual(got.Labels, wantLabels) {
+				t.Errorf("newSnapshot() labels = %v, want %v", got.Labels, wantLabels)
+			}
+			if !reflect.DeepEqual(got.Spec, c.want) {
+				t.Errorf("newSnapshot() spec = %+v, want %+v", got.Spec, c.want)
+			}
+		})
+	}
+}
+
+func TestNewSnapshotDefaultName(t *testing.T) {
+	o := &BackupOptions{Backend: "gs://adhoc/pg", StorageSecret: "gcs-secret"}
+	got, err := o.newSnapshot(api.ResourceKindPostgres, testDatabase(nil))
+	if err != nil {
+		t.Fatalf("newSnapshot() error = %v", err)
+	}
+	// pg-20060102-150405
+	if !strings.HasPrefix(got.Name, "pg-") || len(got.Name) != len("pg-20060102-150405") {
+		t.Errorf("newSnapshot() name = %s, want the database name followed by the current time", got.Name)
+	}
+}
+
+func testSnapshot(phase api.SnapshotPhase) *api.Snapshot {
+	return &api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pg-snap",
+			Namespace: "demo",
+			UID:       "snapshot-uid",
+			Labels:    map[string]string{api.LabelDatabaseKind: api.ResourceKindPostgres, api.LabelDatabaseName: "pg"},
+		},
+		Status: api.SnapshotStatus{Phase: phase, Reason: "bucket not found"},
+	}
+}
+
+func TestFollowSnapshot(t *testing.T) {
+	jobLabels := map[string]string{api.LabelDatabaseKind: api.ResourceKindPostgres}
+	restoreJob := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pg-snap",
+			Namespace:   "demo",
+			Labels:      jobLabels,
+			Annotations: map[string]string{api.AnnotationJobType: api.JobTypeRestore},
+		},
+	}
+	// the backup job is found by its owner, whatever its name
+	backupJob := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "backup-pg-snap-x7k2p",
+			Namespace:       "demo",
+			Labels:          jobLabels,
+			OwnerReferences: []metav1.OwnerReference{{Kind: api.ResourceKindSnapshot, Name: "pg-snap", UID: "snapshot-uid"}},
+		},
+		Status: batch.JobStatus{Succeeded: 1},
+	}
+
+	cases := []struct {
+		name      string
+		phase     api.SnapshotPhase
+		jobs      []*batch.Job
+		jobsError int
+		want      string
+		wantErr   string
+	}{
+		{
+			name:  "job progress",
+			phase: api.SnapshotPhaseSucceeded,
+			jobs:  []*batch.Job{restoreJob, backupJob},
+			want:  "snapshot \"pg-snap\" is Succeeded\njob \"backup-pg-snap-x7k2p\" active: 0, succeeded: 1, failed: 0\n",
+		},
+		{
+			name:  "phase only without a backup job",
+			phase: api.SnapshotPhaseSucceeded,
+			jobs:  []*batch.Job{restoreJob},
+			want:  "snapshot \"pg-snap\" is Succeeded\n",
+		},
+		{
+			name:      "phase only if jobs are forbidden",
+			phase:     api.SnapshotPhaseSucceeded,
+			jobsError: http.StatusForbidden,
+			want:      "snapshot \"pg-snap\" is Succeeded\n",
+		},
+		{
+			name:      "jobs can not be listed",
+			phase:     api.SnapshotPhaseSucceeded,
+			jobsError: http.StatusInternalServerError,
+			want:      "snapshot \"pg-snap\" is Succeeded\n",
+			wantErr:   "injected failure",
+		},
+		{
+			name:    "failed snapshot",
+			phase:   api.SnapshotPhaseFailed,
+			want:    "snapshot \"pg-snap\" is Failed\n",
+			wantErr: `snapshot "pg-snap" failed: bucket not found`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			snapshot := testSnapshot(c.phase)
+			s := fakeapi.NewServer(t)
+			s.Add("/apis/kubedb.com/v1alpha1", api.ResourcePluralSnapshot, snapshot)
+			for _, job := range c.jobs {
+				s.Add("/apis/batch/v1", "jobs", job)
+			}
+			if c.jobsError != 0 {
+				s.Fail("jobs", c.jobsError)
+			}
+
+			client, kubedbClient := testClients(t, s)
+			out := &bytes.Buffer{}
+			o := &BackupOptions{
+				Timeout:      time.Minute,
+				Client:       client,
+				KubedbClient: kubedbClient,
+				IOStreams:    genericclioptions.IOStreams{Out: out},
+			}
+			err := o.followSnapshot(snapshot)
+			if c.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+					t.Fatalf("followSnapshot() error = %v, want %s", err, c.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("followSnapshot() error = %v", err)
+			}
+			if out.String() != c.want {
+				t.Errorf("followSnapshot() printed %q, want %q", out.String(), c.want)
+			}
+		})
+	}
+}
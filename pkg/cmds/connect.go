@@ -0,0 +1,456 @@
+package cmds
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+	"k8s.io/kubernetes/pkg/kubectl/util/term"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/databases"
+)
+
+var (
+	connectLong = templates.LongDesc(`
+		Open a database shell.
+
+		The client of the database, e.g. psql for Postgres, is started in the primary pod of the
+		database, or in a mongos router of a sharded MongoDB, authenticated with the credentials in
+		its database secret. The credentials are written to a temporary file in the pod, readable
+		only by its user, that is removed as soon as the client has read it; they never appear on a
+		command line. Elasticsearch and Etcd do not have an interactive client, so a shell with curl
+		or etcdctl configured is opened instead. The curl shell needs its credentials file until it
+		exits, so a killed Elasticsearch shell can leave it behind in /tmp/kubedb-connect-*.
+
+		Arguments following -- are passed to the client, to run it non-interactively.`)
+
+	connectExample = templates.Examples(`
+		# Open psql in the primary pod of a postgres
+		kubedb connect pg/postgres-demo
+
+		# Run a query in a mysql
+		kubedb connect my/mysql-demo -- -e "SHOW DATABASES"
+
+		# Evaluate an expression in a mongodb
+		kubedb connect mg/mongodb-demo -- "printjson(db.stats())"
+
+		# Check the health of an elasticsearch
+		kubedb connect es/elasticsearch-demo -- http://localhost:9200/_cluster/health
+
+		# Load a dump into a postgres
+		kubedb connect pg/postgres-demo < dump.sql`)
+)
+
+// databaseClient describes how to start the client of a database kind.
+type databaseClient struct {
+	// userKey and passwordKey are the keys of the credentials in the database secret.
+	userKey     string
+	passwordKey string
+	defaultUser string
+	// files returns the files holding the credentials, to be written to dir, along with the
+	// arguments given after --.
+	files func(db *unstructured.Unstructured, dir, user, password string, args []string) map[string]string
+	// script starts the client. It is run by sh, with the directory holding the files as $0
+	// and the arguments given after -- as $@.
+	script func(db *unstructured.Unstructured, interactive bool) string
+}
+
+var databaseClients = map[string]databaseClient{
+	api.ResourceKindPostgres: {
+		userKey:     "POSTGRES_USER",
+		passwordKey: "POSTGRES_PASSWORD",
+		defaultUser: "postgres",
+		files: envFile(func(user, password string) map[string]string {
+			return map[string]string{"PGUSER": user, "PGPASSWORD": password}
+		}),
+		script: func(*unstructured.Unstructured, bool) string {
+			return `. "$0/env" && rm -rf "$0" && exec psql -h localhost "$@"`
+		},
+	},
+	api.ResourceKindMySQL:         mysqlClient,
+	api.ResourceKindMariaDB:       mysqlClient,
+	api.ResourceKindPerconaXtraDB: mysqlClient,
+	api.ResourceKindMongoDB: {
+		userKey:     "username",
+		passwordKey: "password",
+		defaultUser: "root",
+		files: func(_ *unstructured.Unstructured, dir, user, password string, args []string) map[string]string {
+			f, _ := json.Marshal(dir + "/auth.js")
+			u, _ := json.Marshal(user)
+			p, _ := json.Marshal(password)
+			// mongo can not read credentials from the environment, so the script removes itself once
+			// loaded. The arguments are evaluated after authenticating, as --eval would run before.
+			return map[string]string{
+				"auth.js": fmt.Sprintf("removeFile(%s);\ndb.getSiblingDB(\"admin\").auth(%s, %s);\n%s\n", f, u, p, strings.Join(args, " ")),
+			}
+		},
+		script: func(_ *unstructured.Unstructured, interactive bool) string {
+			if interactive {
+				return `trap 'rm -rf "$0"' EXIT HUP INT TERM; mongo --quiet --shell "$0/auth.js"`
+			}
+			return `trap 'rm -rf "$0"' EXIT HUP INT TERM; mongo --quiet "$0/auth.js"`
+		},
+	},
+	api.ResourceKindRedis: {
+		files: func(*unstructured.Unstructured, string, string, string, []string) map[string]string { return nil },
+		script: func(db *unstructured.Unstructured, _ bool) string {
+			if mode, _, _ := unstructured.NestedString(db.Object, "spec", "mode"); mode == string(api.RedisModeCluster) {
+				return `exec redis-cli -c "$@"`
+			}
+			return `exec redis-cli "$@"`
+		},
+	},
+	api.ResourceKindEtcd: {
+		userKey:     "username",
+		passwordKey: "password",
+		defaultUser: "root",
+		files: envFile(func(user, password string) map[string]string {
+			env := map[string]string{"ETCDCTL_API": "3"}
+			if password != "" {
+				env["ETCDCTL_USER"] = user + ":" + password
+			}
+			return env
+		}),
+		script: func(_ *unstructured.Unstructured, interactive bool) string {
+			if interactive {
+				return `. "$0/env" && rm -rf "$0" && echo "Use etcdctl to access the database." && exec sh`
+			}
+			return `. "$0/env" && rm -rf "$0" && exec etcdctl "$@"`
+		},
+	},
+	api.ResourceKindElasticsearch: {
+		userKey:     "ADMIN_USERNAME",
+		passwordKey: "ADMIN_PASSWORD",
+		defaultUser: "admin",
+		files: func(db *unstructured.Unstructured, _, user, password string, _ []string) map[string]string {
+			p, _ := json.Marshal(user + ":" + password)
+			curlrc := fmt.Sprintf("user = %s\n", p)
+			if ssl, _, _ := unstructured.NestedBool(db.Object, "spec", "enableSSL"); ssl {
+				curlrc += "insecure\n"
+			}
+			return map[string]string{".curlrc": curlrc}
+		},
+		script: func(db *unstructured.Unstructured, interactive bool) string {
+			scheme := "http"
+			if ssl, _, _ := unstructured.NestedBool(db.Object, "spec", "enableSSL"); ssl {
+				scheme = "https"
+			}
+			if interactive {
+				return fmt.Sprintf(`trap 'rm -rf "$0"' EXIT HUP INT TERM; export CURL_HOME="$0"; echo "Use curl %s://localhost:%d to access the database."; sh`,
+					scheme, api.ElasticsearchRestPort)
+			}
+			// curl reads its configuration before it connects, from a descriptor to the removed file
+			return `exec 3< "$0/.curlrc" && rm -rf "$0" && exec curl -sS -K /dev/fd/3 "$@"`
+		},
+	},
+}
+
+var mysqlClient = databaseClient{
+	userKey:     "username",
+	passwordKey: "password",
+	defaultUser: "root",
+	files: envFile(func(user, password string) map[string]string {
+		return map[string]string{"MYSQL_USER": user, "MYSQL_PWD": password}
+	}),
+	script: func(*unstructured.Unstructured, bool) string {
+		return `. "$0/env" && rm -rf "$0" && exec mysql -h 127.0.0.1 -u "$MYSQL_USER" "$@"`
+	},
+}
+
+// envFile returns the files of a client that reads its credentials from environment variables.
+func envFile(env func(user, password string) map[string]string) func(*unstructured.Unstructured, string, string, string, []string) map[string]string {
+	return func(_ *unstructured.Unstructured, _, user, password string, _ []string) map[string]string {
+		vars := env(user, password)
+		keys := make([]string, 0, len(vars))
+		for k := range vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var buf bytes.Buffer
+		for _, k := range keys {
+			fmt.Fprintf(&buf, "export %s=%s\n", k, shellQuote(vars[k]))
+		}
+		return map[string]string{"env": buf.String()}
+	}
+}
+
+type ConnectOptions struct {
+	Namespace string
+	Container string
+	Command   []string
+
+	Config *rest.Config
+	Client kubernetes.Interface
+	Result *resource.Result
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdConnect(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &ConnectOptions{
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "connect (TYPE NAME | TYPE/NAME) [-- ARGS...]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Open a shell to a database"),
+		Long:                  connectLong,
+		Example:               connectExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringVarP(&o.Container, "container", "c", o.Container, "Container of the pod to run the client in. Defaults to the first container.")
+	return cmd
+}
+
+func (o *ConnectOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		o.Command = args[dash:]
+		args = args[:dash]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("You must specify the database to connect to.")
+	}
+
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.Result = f.NewBuilder().
+		Unstructured().
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		ResourceTypeOrNameArgs(false, args...).
+		SingleResourceType().
+		Flatten().
+		Do()
+	if err = o.Result.Err(); err != nil {
+		return err
+	}
+
+	o.Config, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *ConnectOptions) Run() error {
+	db, kind, err := singleDatabase(o.Result, "connect to")
+	if err != nil {
+		return err
+	}
+	client, ok := databaseClients[kind]
+	if !ok {
+		return fmt.Errorf("connecting to %s is not supported", kind)
+	}
+
+	selector, err := connectSelector(db, kind)
+	if err != nil {
+		return err
+	}
+	pod, err := primaryPod(o.Client, db.GetNamespace(), selector, kind, db.GetName())
+	if err != nil {
+		return err
+	}
+	container := o.Container
+	if container == "" {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	user, password, err := o.credentials(db, client)
+	if err != nil {
+		return err
+	}
+	dir, err := tempDirName()
+	if err != nil {
+		return err
+	}
+	for name, content := range client.files(db, dir, user, password, o.Command) {
+		err = o.exec(pod, container, []string{"sh", "-c", `umask 077 && mkdir -p "$0" && cat > "$0/$1"`, dir, name},
+			strings.NewReader(content), false)
+		if err != nil {
+			return fmt.Errorf("failed to write credentials to pod %s: %v", pod.Name, err)
+		}
+	}
+
+	interactive := len(o.Command) == 0
+	command := append([]string{"sh", "-c", client.script(db, interactive), dir}, o.Command...)
+	return o.exec(pod, container, command, o.In, interactive)
+}
+
+// credentials reads the user and password from the database secret of db.
+func (o *ConnectOptions) credentials(db *unstructured.Unstructured, client databaseClient) (string, string, error) {
+	name, _, _ := unstructured.NestedString(db.Object, "spec", "databaseSecret", "secretName")
+	if name == "" || client.passwordKey == "" {
+		return client.defaultUser, "", nil
+	}
+	secret, err := o.Client.CoreV1().Secrets(db.GetNamespace()).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	user := string(secret.Data[client.userKey])
+	if user == "" {
+		user = client.defaultUser
+	}
+	return user, string(secret.Data[client.passwordKey]), nil
+}
+
+// exec runs command in the container of pod. With interactive set, a TTY is allocated if stdin is
+// a terminal; otherwise, stdin is only attached if it is not a terminal.
+func (o *ConnectOptions) exec(pod *core.Pod, container string, command []string, stdin io.Reader, interactive bool) error {
+	t := term.TTY{In: stdin, Out: o.Out, Raw: interactive}
+	tty := interactive && t.IsTerminalIn()
+	if !tty {
+		t.Raw = false
+		if stdin == o.In && t.IsTerminalIn() {
+			stdin = nil
+		}
+	}
+	stderr := o.ErrOut
+	var sizeQueue remotecommand.TerminalSizeQueue
+	if tty {
+		// the TTY merges stderr into stdout
+		stderr = nil
+		sizeQueue = t.MonitorSize(t.GetSize())
+	}
+
+	req := o.Client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&core.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    stderr != nil,
+			TTY:       tty,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(o.Config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return t.Safe(func() error {
+		return executor.Stream(remotecommand.StreamOptions{
+			Stdin:             stdin,
+			Stdout:            o.Out,
+			Stderr:            stderr,
+			Tty:               tty,
+			TerminalSizeQueue: sizeQueue,
+		})
+	})
+}
+
+// singleDatabase returns the only KubeDB database in result, along with its kind.
+func singleDatabase(result *resource.Result, action string) (*unstructured.Unstructured, string, error) {
+	infos, err := result.Infos()
+	if err != nil {
+		return nil, "", err
+	}
+	if len(infos) != 1 {
+		return nil, "", fmt.Errorf("You must specify exactly one database to %s, found %d.", action, len(infos))
+	}
+	info := infos[0]
+	gvk := info.Mapping.GroupVersionKind
+	if !databases.IsDatabase(gvk.Kind) || gvk.Group != api.SchemeGroupVersion.Group {
+		return nil, "", fmt.Errorf("%s %q is not a database", gvk.Kind, info.Name)
+	}
+	db, ok := info.Object.(*unstructured.Unstructured)
+	if !ok {
+		return nil, "", fmt.Errorf("unexpected object type %T", info.Object)
+	}
+	return db, gvk.Kind, nil
+}
+
+// connectSelector returns the selector of the pods a shell can be opened in. For a sharded MongoDB
+// these are the mongos routers, as the config servers and shards must not be connected to directly.
+func connectSelector(db *unstructured.Unstructured, kind string) (labels.Selector, error) {
+	if kind == api.ResourceKindMongoDB {
+		_, sharded, err := unstructured.NestedMap(db.Object, "spec", "shardTopology")
+		if err != nil {
+			return nil, err
+		}
+		if sharded {
+			var mongodb api.MongoDB
+			if err = runtime.DefaultUnstructuredConverter.FromUnstructured(db.Object, &mongodb); err != nil {
+				return nil, err
+			}
+			return labels.SelectorFromSet(mongodb.MongosSelectors()), nil
+		}
+	}
+	return labels.SelectorFromSet(map[string]string{
+		api.LabelDatabaseKind: kind,
+		api.LabelDatabaseName: db.GetName(),
+	}), nil
+}
+
+// primaryPod returns the running pod matched by selector that is labeled as primary or master. If no
+// pod has such a role, i.e. for standalone databases and mongos routers, the first running pod is returned.
+func primaryPod(client kubernetes.Interface, namespace string, selector labels.Selector, kind, name string) (*core.Pod, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	var first *core.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != core.PodRunning {
+			continue
+		}
+		switch pod.Labels[api.LabelRole] {
+		case "primary", "master":
+			return pod, nil
+		}
+		if first == nil {
+			first = pod
+		}
+	}
+	if first == nil {
+		return nil, fmt.Errorf("no running pods found for %s %q", strings.ToLower(kind), name)
+	}
+	return first, nil
+}
+
+// tempDirName returns a random path for the credentials written to a pod.
+func tempDirName() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "/tmp/kubedb-connect-" + hex.EncodeToString(b), nil
+}
+
+// shellQuote quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,20 @@
+package cmds
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+func TestMongoDBClientFiles(t *testing.T) {
+	files := databaseClients[api.ResourceKindMongoDB].files(&unstructured.Unstructured{}, "/tmp/kubedb-connect-0123", "root", `pa"ss`, []string{"printjson(db.stats())"})
+
+	want := `removeFile("/tmp/kubedb-connect-0123/auth.js");
+db.getSiblingDB("admin").auth("root", "pa\"ss");
+printjson(db.stats())
+`
+	if got := files["auth.js"]; got != want {
+		t.Errorf("files() auth.js = %q, want %q", got, want)
+	}
+}
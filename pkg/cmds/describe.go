@@ -17,10 +17,14 @@ limitations under the License.
 package cmds
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
+	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -51,17 +55,39 @@ var (
 		# Describe all dormantdatabases
 		kubedb describe drmn
 
+		# Describe a postgres version along with the databases using it
+		kubedb describe postgresversion 10.2-v2
+
+		# Describe a mongodb in YAML output format
+		kubedb describe mg/mongodb-demo -o yaml
+
+		# Describe a mysql along with the contents of its custom configuration files
+		kubedb describe my/mysql-demo --show-config
+
+		# Describe a postgres along with the warning events of its pods, volume claims and snapshot jobs
+		kubedb describe pg/postgres-demo --deep-events --events-type=Warning
+
  		Valid resource types include:
     		* all
     		* etcds
     		* elasticsearches
     		* postgreses
     		* mysqls
+    		* mariadbs
+    		* perconaxtradbs
     		* mongodbs
     		* redises
     		* memcacheds
     		* snapshots
     		* dormantdatabases
+    		* elasticsearchversions
+    		* etcdversions
+    		* memcachedversions
+    		* mongodbversions
+    		* mysqlversions
+    		* perconaxtradbversions
+    		* postgresversions
+    		* redisversions
 `)
 )
 
@@ -78,6 +104,23 @@ type DescribeOptions struct {
 	EnforceNamespace bool
 	AllNamespaces    bool
 
+	// Output is the structured output format, one of json or yaml. Tabbed text is printed if empty.
+	Output string
+
+	// ShowConfig prints the contents of ConfigMap backed configuration files of databases.
+	ShowConfig bool
+
+	// Concurrency is the maximum number of objects described in parallel.
+	Concurrency int
+
+	// Strict fails the command if any part of a description could not be collected.
+	Strict bool
+
+	// DeepEvents merges the events of the objects created for databases with their own events.
+	DeepEvents bool
+	// EventsType only shows events of the given type, if set.
+	EventsType string
+
 	DescriberSettings *describe.DescriberSettings
 	FilenameOptions   *resource.FilenameOptions
 
@@ -91,7 +134,8 @@ func NewCmdDescribe(parent string, f cmdutil.Factory, streams genericclioptions.
 			ShowEvents: true,
 		},
 
-		CmdParent: parent,
+		CmdParent:   parent,
+		Concurrency: 10,
 
 		IOStreams: streams,
 	}
@@ -112,6 +156,12 @@ func NewCmdDescribe(parent string, f cmdutil.Factory, streams genericclioptions.
 	cmd.Flags().StringVarP(&o.Selector, "selector", "l", o.Selector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
 	cmd.Flags().BoolVar(&o.AllNamespaces, "all-namespaces", o.AllNamespaces, "If present, list the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
 	cmd.Flags().BoolVar(&o.DescriberSettings.ShowEvents, "show-events", o.DescriberSettings.ShowEvents, "If true, display events related to the described object.")
+	cmd.Flags().BoolVar(&o.ShowConfig, "show-config", o.ShowConfig, "If true, display the contents of ConfigMap backed configuration files of databases.")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", o.Concurrency, "Maximum number of objects described in parallel.")
+	cmd.Flags().BoolVar(&o.DeepEvents, "deep-events", o.DeepEvents, "If true, include the events of the pods, workloads, volume claims, services, jobs and snapshots of databases, and of the backup jobs of snapshots.")
+	cmd.Flags().StringVar(&o.EventsType, "events-type", o.EventsType, "If set, only display events of the given type. One of: Normal|Warning.")
+	cmd.Flags().BoolVar(&o.Strict, "strict", o.Strict, "If true, exit with a non-zero status if any part of a description could not be collected.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: json|yaml.")
 
 	cmdutil.AddIncludeUninitializedFlag(cmd)
 	return cmd
@@ -128,14 +178,42 @@ func (o *DescribeOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args [
 		o.EnforceNamespace = false
 	}
 
+	if o.Output != "" && o.Output != "json" && o.Output != "yaml" {
+		return fmt.Errorf("unsupported output format %q, allowed formats are: json, yaml", o.Output)
+	}
+
+	if o.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, found %d", o.Concurrency)
+	}
+
+	if o.EventsType != "" && o.EventsType != core.EventTypeNormal && o.EventsType != core.EventTypeWarning {
+		return fmt.Errorf("unsupported events type %q, allowed types are: %s, %s", o.EventsType, core.EventTypeNormal, core.EventTypeWarning)
+	}
+
 	if len(args) == 0 && cmdutil.IsFilenameSliceEmpty(o.FilenameOptions.Filenames, o.FilenameOptions.Kustomize) {
 		return fmt.Errorf("You must specify the type of resource to describe. %s\n", cmdutil.SuggestAPIResources(o.CmdParent))
 	}
 
 	o.BuilderArgs = args
 
+	// describers of this invocation share their clients and the objects they look up
+	describerFn := describer.NewDescriberFunc()
 	o.Describer = func(mapping *meta.RESTMapping) (describe.Describer, error) {
-		return describer.DescriberFn(f, mapping)
+		d, err := describerFn(f, mapping)
+		if err != nil {
+			return nil, err
+		}
+		if cd, ok := d.(describer.ConfigDescriber); ok {
+			cd.ShowConfig(o.ShowConfig)
+		}
+		if sd, ok := d.(describer.StrictDescriber); ok {
+			sd.Strict(o.Strict)
+		}
+		if ed, ok := d.(describer.EventsDescriber); ok {
+			ed.DeepEvents(o.DeepEvents)
+			ed.EventsType(o.EventsType)
+		}
+		return d, nil
 	}
 
 	o.NewBuilder = f.NewBuilder
@@ -173,42 +251,34 @@ func (o *DescribeOptions) Run() error {
 
 	errs := sets.NewString()
 	first := true
-	for _, info := range infos {
-		mapping := info.ResourceMapping()
-		describer, err := o.Describer(mapping)
-		if err != nil {
-			if errs.Has(err.Error()) {
-				continue
-			}
-			allErrs = append(allErrs, err)
-			errs.Insert(err.Error())
-			continue
+	results := o.describeInfos(infos, func(info *resource.Info) (describe.Describer, error) {
+		return o.Describer(info.ResourceMapping())
+	})
+	for _, r := range results {
+		if r.err != nil && !errs.Has(r.err.Error()) {
+			allErrs = append(allErrs, r.err)
+			errs.Insert(r.err.Error())
 		}
-		s, err := describer.Describe(info.Namespace, info.Name, *o.DescriberSettings)
-		if err != nil {
-			if errs.Has(err.Error()) {
-				continue
-			}
-			allErrs = append(allErrs, err)
-			errs.Insert(err.Error())
+		// incomplete descriptions are printed, along with the error
+		if r.err != nil && !describer.IsIncompleteDescription(r.err) {
 			continue
 		}
-		if first {
+		if first || o.Output != "" {
 			first = false
-			fmt.Fprint(o.Out, s)
+			fmt.Fprint(o.Out, r.out)
 		} else {
-			fmt.Fprintf(o.Out, "\n\n%s", s)
+			fmt.Fprintf(o.Out, "\n\n%s", r.out)
 		}
 	}
 
 	return utilerrors.NewAggregate(allErrs)
 }
 
-func (o *DescribeOptions) DescribeMatchingResources(originalError error, resource, prefix string) error {
+func (o *DescribeOptions) DescribeMatchingResources(originalError error, resourceType, prefix string) error {
 	r := o.NewBuilder().
 		Unstructured().
 		NamespaceParam(o.Namespace).DefaultNamespace().
-		ResourceTypeOrNameArgs(true, resource).
+		ResourceTypeOrNameArgs(true, resourceType).
 		SingleResourceType().
 		Flatten().
 		Do()
@@ -216,7 +286,7 @@ func (o *DescribeOptions) DescribeMatchingResources(originalError error, resourc
 	if err != nil {
 		return err
 	}
-	describer, err := o.Describer(mapping)
+	d, err := o.Describer(mapping)
 	if err != nil {
 		return err
 	}
@@ -224,20 +294,98 @@ func (o *DescribeOptions) DescribeMatchingResources(originalError error, resourc
 	if err != nil {
 		return err
 	}
-	isFound := false
-	for ix := range infos {
-		info := infos[ix]
+	var matched []*resource.Info
+	for _, info := range infos {
 		if strings.HasPrefix(info.Name, prefix) {
-			isFound = true
-			s, err := describer.Describe(info.Namespace, info.Name, *o.DescriberSettings)
-			if err != nil {
-				return err
-			}
-			fmt.Fprintf(o.Out, "%s\n", s)
+			matched = append(matched, info)
 		}
 	}
-	if !isFound {
+	if len(matched) == 0 {
 		return originalError
 	}
-	return nil
+	results := o.describeInfos(matched, func(*resource.Info) (describe.Describer, error) {
+		return d, nil
+	})
+	allErrs := []error{}
+	for _, r := range results {
+		if r.err != nil {
+			if !describer.IsIncompleteDescription(r.err) {
+				return r.err
+			}
+			allErrs = append(allErrs, r.err)
+		}
+		if o.Output != "" {
+			fmt.Fprint(o.Out, r.out)
+		} else {
+			fmt.Fprintf(o.Out, "%s\n", r.out)
+		}
+	}
+	return utilerrors.NewAggregate(allErrs)
+}
+
+type describeResult struct {
+	out string
+	err error
+}
+
+// describeInfos describes the given objects using up to Concurrency workers. The results are
+// returned in the order of infos, regardless of the order in which the descriptions complete.
+func (o *DescribeOptions) describeInfos(infos []*resource.Info, describerFor func(*resource.Info) (describe.Describer, error)) []describeResult {
+	results := make([]describeResult, len(infos))
+
+	// Describers are looked up serially, as building them is not safe for concurrent use.
+	describers := make([]describe.Describer, len(infos))
+	for i, info := range infos {
+		describers[i], results[i].err = describerFor(info)
+	}
+
+	sem := make(chan struct{}, o.Concurrency)
+	var wg sync.WaitGroup
+	for i := range infos {
+		if describers[i] == nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i].out, results[i].err = o.describe(describers[i], infos[i].Namespace, infos[i].Name)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// describe returns the description of the named object, either as tabbed text or
+// as a JSON or YAML document depending on the requested output format.
+func (o *DescribeOptions) describe(d describe.Describer, namespace, name string) (string, error) {
+	if o.Output == "" {
+		return d.Describe(namespace, name, *o.DescriberSettings)
+	}
+
+	od, ok := d.(describer.ObjectDescriber)
+	if !ok {
+		return "", fmt.Errorf("output format %q is not supported for %s/%s", o.Output, namespace, name)
+	}
+	desc, err := od.DescribeObject(namespace, name, *o.DescriberSettings)
+	// an incomplete description is printed along with the error, as its Warnings tell what is missing
+	if err != nil && !describer.IsIncompleteDescription(err) {
+		return "", err
+	}
+
+	if o.Output == "yaml" {
+		data, merr := yaml.Marshal(desc)
+		if merr != nil {
+			return "", merr
+		}
+		return "---\n" + string(data), err
+	}
+	data, merr := json.MarshalIndent(desc, "", "    ")
+	if merr != nil {
+		return "", merr
+	}
+	return string(data) + "\n", err
 }
@@ -0,0 +1,133 @@
+package cmds
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/kubectl/describe"
+	"kubedb.dev/cli/pkg/describer"
+)
+
+// objectDescriber returns a fixed description, both as an object and as text.
+type objectDescriber struct {
+	desc *describer.Description
+	err  error
+}
+
+func (d objectDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+	return "Name:\t" + d.desc.Name + "\n", d.err
+}
+
+func (d objectDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*describer.Description, error) {
+	return d.desc, d.err
+}
+
+// textDescriber only describes objects as text, like the generic describers of other kinds.
+type textDescriber struct{}
+
+func (textDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+	return "Name:\t" + name + "\n", nil
+}
+
+func TestDescribeOutput(t *testing.T) {
+	desc := &describer.Description{
+		Kind:              "Postgres",
+		Name:              "pg",
+		Namespace:         "demo",
+		CreationTimestamp: metav1.NewTime(time.Date(2019, time.August, 15, 12, 0, 0, 0, time.UTC)),
+		Status:            "Running",
+		Spec:              &describer.SpecSummary{Version: "10.2-v2"},
+	}
+	incomplete := *desc
+	incomplete.Warnings = []string{"Database Secret pg-auth: forbidden"}
+	incompleteErr := &describer.IncompleteDescriptionError{Warnings: incomplete.Warnings}
+	errNotFound := errors.New(`postgreses.kubedb.com "pg" not found`)
+
+	cases := []struct {
+		name    string
+		output  string
+		d       describe.Describer
+		want    string
+		wantErr error
+	}{
+		{
+			name: "text",
+			d:    objectDescriber{desc: desc},
+			want: "Name:\tpg\n",
+		},
+		{
+			name:   "json",
+			output: "json",
+			d:      objectDescriber{desc: desc},
+			want: `{
+    "kind": "Postgres",
+    "name": "pg",
+    "namespace": "demo",
+    "creationTimestamp": "2019-08-15T12:00:00Z",
+    "status": "Running",
+    "spec": {
+        "version": "10.2-v2"
+    }
+}
+`,
+		},
+		{
+			name:   "yaml",
+			output: "yaml",
+			d:      objectDescriber{desc: desc},
+			want: `---
+creationTimestamp: "2019-08-15T12:00:00Z"
+kind: Postgres
+name: pg
+namespace: demo
+spec:
+  version: 10.2-v2
+status: Running
+`,
+		},
+		{
+			name:    "incomplete description is rendered with its warnings",
+			output:  "yaml",
+			d:       objectDescriber{desc: &incomplete, err: incompleteErr},
+			wantErr: incompleteErr,
+			want: `---
+creationTimestamp: "2019-08-15T12:00:00Z"
+kind: Postgres
+name: pg
+namespace: demo
+spec:
+  version: 10.2-v2
+status: Running
+warnings:
+- 'Database Secret pg-auth: forbidden'
+`,
+		},
+		{
+			name:    "failed description is not rendered",
+			output:  "json",
+			d:       objectDescriber{err: errNotFound},
+			wantErr: errNotFound,
+		},
+		{
+			name:    "structured output of a text only describer",
+			output:  "json",
+			d:       textDescriber{},
+			wantErr: errors.New(`output format "json" is not supported for demo/pg`),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := &DescribeOptions{Output: c.output, DescriberSettings: &describe.DescriberSettings{}}
+			got, err := o.describe(c.d, "demo", "pg")
+			if (err == nil) != (c.wantErr == nil) || err != nil && err.Error() != c.wantErr.Error() {
+				t.Errorf("describe() error = %v, want %v", err, c.wantErr)
+			}
+			if got != c.want {
+				t.Errorf("describe() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
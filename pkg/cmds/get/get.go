@@ -37,6 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	watchtools "k8s.io/client-go/tools/watch"
 	"k8s.io/kubernetes/pkg/api/legacyscheme"
@@ -110,6 +111,8 @@ var (
     		* elasticsearches
     		* postgreses
     		* mysqls
+    		* mariadbs
+    		* perconaxtradbs
     		* mongodbs
     		* redises
     		* memcacheds
@@ -424,7 +427,8 @@ func (o *GetOptions) Run(f cmdutil.Factory, cmd *cobra.Command, args []string) e
 			req.SetHeader("Accept", tableParam)
 
 			// if sorting, ensure we receive the full object in order to introspect its fields via jsonpath
-			if o.Sort {
+			// wide output needs the full object as well to resolve the catalog version of databases
+			if o.Sort || o.isWide() {
 				req.Param("includeObject", "Object")
 			}
 		}).
@@ -449,11 +453,21 @@ func (o *GetOptions) Run(f cmdutil.Factory, cmd *cobra.Command, args []string) e
 	}
 	printWithKind := multipleGVKsRequested(infos)
 
+	var dc dynamic.Interface
+	if o.isWide() {
+		if dc, err = f.DynamicClient(); err != nil {
+			return err
+		}
+	}
+
 	objs := make([]runtime.Object, len(infos))
 	for ix := range infos {
 		if o.ServerPrint {
 			table, err := o.decodeIntoTable(infos[ix].Object)
 			if err == nil {
+				if dc != nil {
+					addVersionStatusColumn(dc, infos[ix].Mapping, table.(*metav1beta1.Table))
+				}
 				infos[ix].Object = table
 			} else {
 				// if we are unable to decode server response into a v1beta1.Table,
@@ -555,6 +569,10 @@ func (o *GetOptions) Run(f cmdutil.Factory, cmd *cobra.Command, args []string) e
 	return utilerrors.NewAggregate(allErrs)
 }
 
+func (o *GetOptions) isWide() bool {
+	return o.PrintFlags.OutputFormat != nil && *o.PrintFlags.OutputFormat == "wide"
+}
+
 // raw makes a simple HTTP request to the provided path on the server using the default
 // credentials.
 func (o *GetOptions) raw(f cmdutil.Factory) error {
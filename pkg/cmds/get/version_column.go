@@ -0,0 +1,65 @@
+package get
+
+import (
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/client-go/dynamic"
+	"kubedb.dev/apimachinery/apis/kubedb"
+	"kubedb.dev/cli/pkg/describer"
+)
+
+const (
+	versionStatusActive     = "Active"
+	versionStatusDeprecated = "Deprecated"
+	versionStatusMissing    = "Missing"
+	versionStatusUnknown    = "<unknown>"
+)
+
+// addVersionStatusColumn appends a "Version Status" column to a server side printed table of
+// KubeDB databases, flagging rows whose catalog version is deprecated or does not exist.
+func addVersionStatusColumn(dc dynamic.Interface, mapping *meta.RESTMapping, table *metav1beta1.Table) {
+	if mapping == nil || mapping.GroupVersionKind.Group != kubedb.GroupName {
+		return
+	}
+	kind := mapping.GroupVersionKind.Kind
+	if !describer.HasCatalogVersion(kind) {
+		return
+	}
+
+	table.ColumnDefinitions = append(table.ColumnDefinitions, metav1beta1.TableColumnDefinition{
+		Name:        "Version Status",
+		Type:        "string",
+		Description: "Whether the catalog version used by the database is active, deprecated or missing.",
+		Priority:    1,
+	})
+
+	statuses := make(map[string]string)
+	for i := range table.Rows {
+		row := &table.Rows[i]
+		status := versionStatusUnknown
+		if u, ok := row.Object.Object.(*unstructured.Unstructured); ok {
+			if version, found, _ := unstructured.NestedString(u.Object, "spec", "version"); found {
+				if _, ok := statuses[version]; !ok {
+					statuses[version] = versionStatus(dc, kind, version)
+				}
+				status = statuses[version]
+			}
+		}
+		row.Cells = append(row.Cells, status)
+	}
+}
+
+func versionStatus(dc dynamic.Interface, kind, version string) string {
+	cv, err := describer.GetCatalogVersion(dc, kind, version)
+	switch {
+	case kapierrors.IsNotFound(err):
+		return versionStatusMissing
+	case err != nil:
+		return versionStatusUnknown
+	case cv.Deprecated:
+		return versionStatusDeprecated
+	}
+	return versionStatusActive
+}
@@ -0,0 +1,193 @@
+package cmds
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/databases"
+)
+
+// pollInterval is how often the pause, resume and related commands check the status of the objects they wait for.
+const pollInterval = 2 * time.Second
+
+var (
+	pauseLong = templates.LongDesc(`
+		Pause a database.
+
+		The database is deleted, while its data and configuration are kept. The KubeDB operator
+		then creates a DormantDatabase with the same name, from which the database can be resumed
+		using 'kubedb resume'. Only databases with terminationPolicy Pause can be paused.`)
+
+	pauseExample = templates.Examples(`
+		# Pause a postgres
+		kubedb pause pg/postgres-demo
+
+		# Pause a mongodb without waiting for its DormantDatabase
+		kubedb pause mongodb mongodb-demo --wait=false`)
+)
+
+type PauseOptions struct {
+	Namespace string
+
+	Wait    bool
+	Timeout time.Duration
+
+	DynamicClient dynamic.Interface
+	KubedbClient  cs.KubedbV1alpha1Interface
+	Result        *resource.Result
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdPause(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &PauseOptions{
+		Wait:      true,
+		Timeout:   5 * time.Minute,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "pause (TYPE NAME | TYPE/NAME)",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Pause a database, keeping its data for a later resume"),
+		Long:                  pauseLong,
+		Example:               pauseExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "If true, wait for the DormantDatabase of the database to be paused.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait, zero means wait forever.")
+	return cmd
+}
+
+func (o *PauseOptions) Complete(f cmdutil.Factory, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("You must specify the database to pause.")
+	}
+
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.Result = f.NewBuilder().
+		Unstructured().
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		ResourceTypeOrNameArgs(false, args...).
+		Flatten().
+		Do()
+	if err = o.Result.Err(); err != nil {
+		return err
+	}
+
+	o.DynamicClient, err = f.DynamicClient()
+	if err != nil {
+		return err
+	}
+	o.KubedbClient, err = newKubedbClient(f)
+	return err
+}
+
+func (o *PauseOptions) Run() error {
+	infos, err := o.Result.Infos()
+	if err != nil {
+		return err
+	}
+
+	// all databases are checked before any is deleted, so that a bad argument doesn't leave the others partly paused
+	for _, info := range infos {
+		gvk := info.Mapping.GroupVersionKind
+		if !databases.IsDatabase(gvk.Kind) || gvk.Group != api.SchemeGroupVersion.Group {
+			return fmt.Errorf("%s %q is not a database, only databases can be paused", gvk.Kind, info.Name)
+		}
+		obj, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected object type %T", info.Object)
+		}
+		policy, _, _ := unstructured.NestedString(obj.Object, "spec", "terminationPolicy")
+		if policy != string(api.TerminationPolicyPause) {
+			return fmt.Errorf("%s %q has terminationPolicy %q, only databases with terminationPolicy %q can be paused",
+				gvk.Kind, info.Name, policy, api.TerminationPolicyPause)
+		}
+	}
+
+	for _, info := range infos {
+		propagation := metav1.DeletePropagationBackground
+		err = o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).Delete(info.Name, &metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s %q pausing\n", resourceString(info), info.Name)
+
+		if !o.Wait {
+			continue
+		}
+		if err = o.waitForDormantDatabase(info.Namespace, info.Name); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s %q paused\n", resourceString(info), info.Name)
+	}
+	return nil
+}
+
+// waitForDormantDatabase waits for the operator to create the DormantDatabase of a deleted database and finish pausing it.
+func (o *PauseOptions) waitForDormantDatabase(namespace, name string) error {
+	err := poll(o.Timeout, func() (bool, error) {
+		drmn, err := o.KubedbClient.DormantDatabases(namespace).Get(name, metav1.GetOptions{})
+		if kerr.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return drmn.Status.Phase == api.DormantDatabasePhasePaused, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for dormantdatabase %q to be paused", name)
+	}
+	return err
+}
+
+// newKubedbClient returns a client for the KubeDB API group.
+func newKubedbClient(f cmdutil.Factory) (cs.KubedbV1alpha1Interface, error) {
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cs.NewForConfig(config)
+}
+
+// poll calls condition until it is done or the timeout expires. A zero timeout means wait forever.
+func poll(timeout time.Duration, condition wait.ConditionFunc) error {
+	if timeout == 0 {
+		return wait.PollImmediateInfinite(pollInterval, condition)
+	}
+	return wait.PollImmediate(pollInterval, timeout, condition)
+}
+
+// resourceString returns the lower case kind of the object along with its group, i.e. postgres.kubedb.com.
+func resourceString(info *resource.Info) string {
+	gvk := info.Mapping.GroupVersionKind
+	if len(gvk.Group) == 0 {
+		return strings.ToLower(gvk.Kind)
+	}
+	return fmt.Sprintf("%s.%s", strings.ToLower(gvk.Kind), gvk.Group)
+}
@@ -0,0 +1,433 @@
+package cmds
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+var (
+	portForwardLong = templates.LongDesc(`
+		Forward a local port to a database.
+
+		The port of the primary service of the database is forwarded to one of the ready pods behind
+		the service, and a connection URI is printed with the user of the database secret. If the pod
+		goes away or stops being ready, connections are forwarded to another ready pod of the
+		service as soon as there is one. If no local port is given, a random port is used.`)
+
+	portForwardExample = templates.Examples(`
+		# Forward a random local port to a postgres
+		kubedb port-forward pg/postgres-demo
+
+		# Forward local port 5432 to the standby replicas of a postgres
+		kubedb port-forward pg/postgres-demo 5432 --replica
+
+		# Forward local port 27017 to a mongodb, listening on all addresses
+		kubedb port-forward mg/mongodb-demo 27017 --address 0.0.0.0`)
+)
+
+type PortForwardOptions struct {
+	Namespace string
+	LocalPort int
+	Address   string
+	Replica   bool
+
+	Config *rest.Config
+	Client kubernetes.Interface
+	Result *resource.Result
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdPortForward(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &PortForwardOptions{
+		Address:   "localhost",
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "port-forward (TYPE/NAME | TYPE NAME) [LOCAL_PORT]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Forward a local port to a database"),
+		Long:                  portForwardLong,
+		Example:               portForwardExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringVar(&o.Address, "address", o.Address, "Address to listen on.")
+	cmd.Flags().BoolVar(&o.Replica, "replica", o.Replica, "If true, forward to the standby replicas instead of the primary. Only supported for Postgres.")
+	return cmd
+}
+
+func (o *PortForwardOptions) Complete(f cmdutil.Factory, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("You must specify the database to forward to.")
+	}
+	// the local port follows either TYPE/NAME or TYPE NAME
+	if last := args[len(args)-1]; len(args) == 3 || (len(args) == 2 && strings.Contains(args[0], "/")) {
+		port, err := strconv.Atoi(last)
+		if err != nil || port < 0 || port > 65535 {
+			return fmt.Errorf("invalid local port %q", last)
+		}
+		o.LocalPort = port
+		args = args[:len(args)-1]
+	}
+
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.Result = f.NewBuilder().
+		Unstructured().
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		ResourceTypeOrNameArgs(false, args...).
+		SingleResourceType().
+		Flatten().
+		Do()
+	if err = o.Result.Err(); err != nil {
+		return err
+	}
+
+	o.Config, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *PortForwardOptions) Run() error {
+	db, kind, err := singleDatabase(o.Result, "forward to")
+	if err != nil {
+		return err
+	}
+	serviceName, err := databaseServiceName(kind, db.GetName(), o.Replica)
+	if err != nil {
+		return err
+	}
+	svc, err := o.Client.CoreV1().Services(db.GetNamespace()).Get(serviceName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if len(svc.Spec.Ports) == 0 || len(svc.Spec.Selector) == 0 {
+		return fmt.Errorf("service %q has no ports or no selector", svc.Name)
+	}
+	user, err := o.user(db, kind)
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	defer signal.Stop(stop)
+
+	t := &tunnel{
+		options:   o,
+		service:   svc,
+		localPort: o.LocalPort,
+		listening: func(localPort int) {
+			fmt.Fprintf(o.Out, "Forwarding from %s to service %q\n", net.JoinHostPort(o.Address, strconv.Itoa(localPort)), svc.Name)
+			fmt.Fprintf(o.Out, "Connect with: %s\n", connectionURI(db, kind, user, localPort))
+			if name, _, _ := unstructured.NestedString(db.Object, "spec", "databaseSecret", "secretName"); name != "" {
+				if client, ok := databaseClients[kind]; ok && client.passwordKey != "" {
+					fmt.Fprintf(o.Out, "The password is the %s key of secret %q\n", client.passwordKey, name)
+				}
+			}
+		},
+	}
+	return t.run(stop)
+}
+
+// tunnel forwards the local port to the ready pods of a service, switching to another pod whenever
+// the current one goes away or stops being ready.
+type tunnel struct {
+	options *PortForwardOptions
+	service *core.Service
+	// localPort is the port listened on, it is set by the first forwarder if a random port is requested
+	localPort int
+	// listening is called once the local port is listened on for the first time
+	listening func(localPort int)
+}
+
+func (t *tunnel) run(stop <-chan os.Signal) error {
+	for {
+		var pod *core.Pod
+		err := poll(0, func() (bool, error) {
+			select {
+			case <-stop:
+				return false, errInterrupted
+			default:
+			}
+			var err error
+			pod, err = readyPod(t.options.Client, t.service)
+			return pod != nil, err
+		})
+		if err == errInterrupted {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		port, err := targetPort(t.service, pod)
+		if err != nil {
+			return err
+		}
+		done, err := t.forward(pod, port, stop)
+		if done || err != nil {
+			return err
+		}
+	}
+}
+
+// errInterrupted stops polling for a ready pod once the command is interrupted.
+var errInterrupted = fmt.Errorf("interrupted")
+
+// podDialer records the error of dialing the pod, to tell it apart from failing to listen locally.
+type podDialer struct {
+	httpstream.Dialer
+	err error
+}
+
+func (d *podDialer) Dial(protocols ...string) (httpstream.Connection, string, error) {
+	conn, protocol, err := d.Dialer.Dial(protocols...)
+	d.err = err
+	return conn, protocol, err
+}
+
+func (t *tunnel) dialer(pod *core.Pod) (*podDialer, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(t.options.Config)
+	if err != nil {
+		return nil, err
+	}
+	req := t.options.Client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+	return &podDialer{Dialer: spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())}, nil
+}
+
+// forward forwards the local port to the port of pod until the pod is lost, in which case it returns
+// false, or the command is interrupted.
+func (t *tunnel) forward(pod *core.Pod, port int32, stop <-chan os.Signal) (bool, error) {
+	dialer, err := t.dialer(pod)
+	if err != nil {
+		return true, err
+	}
+	podStop := make(chan struct{})
+	ready := make(chan struct{})
+	fw, err := portforward.NewOnAddresses(dialer, []string{t.options.Address}, []string{fmt.Sprintf("%d:%d", t.localPort, port)},
+		podStop, ready, nil, t.options.ErrOut)
+	if err != nil {
+		return true, err
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- fw.ForwardPorts()
+	}()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ready:
+			ready = nil
+			if t.localPort == 0 {
+				ports, err := fw.GetPorts()
+				if err != nil {
+					close(podStop)
+					<-errs
+					return true, err
+				}
+				t.localPort = int(ports[0].Local)
+			}
+			if t.listening != nil {
+				t.listening(t.localPort)
+				t.listening = nil
+			}
+			fmt.Fprintf(t.options.Out, "Forwarding to pod %q port %d\n", pod.Name, port)
+		case err := <-errs:
+			if dialer.err != nil {
+				fmt.Fprintf(t.options.ErrOut, "failed to connect to pod %q: %v\n", pod.Name, dialer.err)
+				time.Sleep(pollInterval)
+				return false, nil
+			} else if err != nil {
+				return true, err
+			}
+			fmt.Fprintf(t.options.Out, "Lost pod %q, waiting for a ready pod\n", pod.Name)
+			return false, nil
+		case <-ticker.C:
+			current, err := t.options.Client.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+			if kerr.IsNotFound(err) || (err == nil && (current.UID != pod.UID || !isPodReady(current))) {
+				close(podStop)
+				<-errs
+				fmt.Fprintf(t.options.Out, "Lost pod %q, waiting for a ready pod\n", pod.Name)
+				return false, nil
+			}
+		case <-stop:
+			close(podStop)
+			<-errs
+			return true, nil
+		}
+	}
+}
+
+// user returns the user of the database secret, or the default user of the database.
+func (o *PortForwardOptions) user(db *unstructured.Unstructured, kind string) (string, error) {
+	client, ok := databaseClients[kind]
+	if !ok {
+		return "", nil
+	}
+	name, _, _ := unstructured.NestedString(db.Object, "spec", "databaseSecret", "secretName")
+	if name == "" || client.userKey == "" {
+		return client.defaultUser, nil
+	}
+	secret, err := o.Client.CoreV1().Secrets(db.GetNamespace()).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if user := string(secret.Data[client.userKey]); user != "" {
+		return user, nil
+	}
+	return client.defaultUser, nil
+}
+
+// databaseServiceName returns the name of the primary service of a database, or of the service of
+// its standby replicas.
+func databaseServiceName(kind, name string, replica bool) (string, error) {
+	meta := metav1.ObjectMeta{Name: name}
+	if replica && kind != api.ResourceKindPostgres {
+		return "", fmt.Errorf("--replica is only supported for %s", api.ResourceKindPostgres)
+	}
+	switch kind {
+	case api.ResourceKindPostgres:
+		if replica {
+			return api.Postgres{ObjectMeta: meta}.ReplicasServiceName(), nil
+		}
+		return api.Postgres{ObjectMeta: meta}.ServiceName(), nil
+	case api.ResourceKindElasticsearch:
+		return api.Elasticsearch{ObjectMeta: meta}.ServiceName(), nil
+	case api.ResourceKindEtcd:
+		return api.Etcd{ObjectMeta: meta}.ClientServiceName(), nil
+	case api.ResourceKindMariaDB:
+		return api.MariaDB{ObjectMeta: meta}.ServiceName(), nil
+	case api.ResourceKindMemcached:
+		return api.Memcached{ObjectMeta: meta}.ServiceName(), nil
+	case api.ResourceKindMongoDB:
+		return api.MongoDB{ObjectMeta: meta}.ServiceName(), nil
+	case api.ResourceKindMySQL:
+		return api.MySQL{ObjectMeta: meta}.ServiceName(), nil
+	case api.ResourceKindPerconaXtraDB:
+		return api.PerconaXtraDB{ObjectMeta: meta}.ServiceName(), nil
+	case api.ResourceKindRedis:
+		return api.Redis{ObjectMeta: meta}.ServiceName(), nil
+	}
+	return "", fmt.Errorf("forwarding to %s is not supported", kind)
+}
+
+// connectionURI returns the URI to connect to the database through the local port.
+func connectionURI(db *unstructured.Unstructured, kind, user string, port int) string {
+	host := net.JoinHostPort("localhost", strconv.Itoa(port))
+	switch kind {
+	case api.ResourceKindPostgres:
+		return fmt.Sprintf("postgresql://%s@%s/postgres", user, host)
+	case api.ResourceKindMySQL, api.ResourceKindMariaDB, api.ResourceKindPerconaXtraDB:
+		return fmt.Sprintf("mysql://%s@%s", user, host)
+	case api.ResourceKindMongoDB:
+		return fmt.Sprintf("mongodb://%s@%s/?authSource=admin", user, host)
+	case api.ResourceKindRedis:
+		return fmt.Sprintf("redis://%s", host)
+	case api.ResourceKindMemcached:
+		return fmt.Sprintf("memcached://%s", host)
+	case api.ResourceKindEtcd:
+		scheme := "http"
+		if _, tls, _ := unstructured.NestedMap(db.Object, "spec", "tls"); tls {
+			scheme = "https"
+		}
+		return fmt.Sprintf("%s://%s", scheme, host)
+	case api.ResourceKindElasticsearch:
+		scheme := "http"
+		if ssl, _, _ := unstructured.NestedBool(db.Object, "spec", "enableSSL"); ssl {
+			scheme = "https"
+		}
+		return fmt.Sprintf("%s://%s@%s", scheme, user, host)
+	}
+	return host
+}
+
+// readyPod returns the first ready pod selected by the service, or nil if there is none.
+func readyPod(client kubernetes.Interface, svc *core.Service) (*core.Pod, error) {
+	selector := labels.SelectorFromSet(svc.Spec.Selector)
+	pods, err := client.CoreV1().Pods(svc.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+	for i := range pods.Items {
+		if isPodReady(&pods.Items[i]) {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func isPodReady(pod *core.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != core.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == core.PodReady {
+			return c.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
+
+// targetPort returns the container port of the pod the first port of the service forwards to.
+func targetPort(svc *core.Service, pod *core.Pod) (int32, error) {
+	sp := svc.Spec.Ports[0]
+	switch {
+	case sp.TargetPort.Type == intstr.Int && sp.TargetPort.IntVal == 0:
+		return sp.Port, nil
+	case sp.TargetPort.Type == intstr.Int:
+		return sp.TargetPort.IntVal, nil
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == sp.TargetPort.StrVal {
+				return p.ContainerPort, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("pod %q has no port named %q", pod.Name, sp.TargetPort.StrVal)
+}
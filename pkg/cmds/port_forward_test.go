@@ -0,0 +1,118 @@
+package cmds
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+func TestDatabaseServiceName(t *testing.T) {
+	cases := []struct {
+		kind    string
+		replica bool
+		want    string
+		wantErr string
+	}{
+		{kind: api.ResourceKindPostgres, want: "demo"},
+		{kind: api.ResourceKindPostgres, replica: true, want: "demo-replicas"},
+		{kind: api.ResourceKindElasticsearch, want: "demo"},
+		{kind: api.ResourceKindEtcd, want: "demo-client"},
+		{kind: api.ResourceKindMariaDB, want: "demo"},
+		{kind: api.ResourceKindMemcached, want: "demo"},
+		{kind: api.ResourceKindMongoDB, want: "demo"},
+		{kind: api.ResourceKindMySQL, want: "demo"},
+		{kind: api.ResourceKindPerconaXtraDB, want: "demo"},
+		{kind: api.ResourceKindRedis, want: "demo"},
+		{kind: api.ResourceKindMySQL, replica: true, wantErr: "--replica is only supported for Postgres"},
+		{kind: api.ResourceKindSnapshot, wantErr: "forwarding to Snapshot is not supported"},
+	}
+	for _, c := range cases {
+		got, err := databaseServiceName(c.kind, "demo", c.replica)
+		if c.wantErr != "" {
+			if err == nil || err.Error() != c.wantErr {
+				t.Errorf("databaseServiceName(%s, replica: %v) error = %v, want %s", c.kind, c.replica, err, c.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("databaseServiceName(%s, replica: %v) = %q, %v, want %q", c.kind, c.replica, got, err, c.want)
+		}
+	}
+}
+
+func TestConnectionURI(t *testing.T) {
+	db := func(spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	}
+	cases := []struct {
+		kind string
+		db   *unstructured.Unstructured
+		user string
+		want string
+	}{
+		{kind: api.ResourceKindPostgres, user: "postgres", want: "postgresql://postgres@localhost:5432/postgres"},
+		{kind: api.ResourceKindMySQL, user: "root", want: "mysql://root@localhost:5432"},
+		{kind: api.ResourceKindMariaDB, user: "root", want: "mysql://root@localhost:5432"},
+		{kind: api.ResourceKindPerconaXtraDB, user: "root", want: "mysql://root@localhost:5432"},
+		{kind: api.ResourceKindMongoDB, user: "root", want: "mongodb://root@localhost:5432/?authSource=admin"},
+		{kind: api.ResourceKindRedis, want: "redis://localhost:5432"},
+		{kind: api.ResourceKindMemcached, want: "memcached://localhost:5432"},
+		{kind: api.ResourceKindEtcd, want: "http://localhost:5432"},
+		{kind: api.ResourceKindEtcd, db: db(map[string]interface{}{"tls": map[string]interface{}{}}), want: "https://localhost:5432"},
+		{kind: api.ResourceKindElasticsearch, user: "admin", want: "http://admin@localhost:5432"},
+		{kind: api.ResourceKindElasticsearch, db: db(map[string]interface{}{"enableSSL": true}), user: "admin", want: "https://admin@localhost:5432"},
+	}
+	for _, c := range cases {
+		d := c.db
+		if d == nil {
+			d = db(map[string]interface{}{})
+		}
+		if got := connectionURI(d, c.kind, c.user, 5432); got != c.want {
+			t.Errorf("connectionURI(%s, %v) = %q, want %q", c.kind, d.Object["spec"], got, c.want)
+		}
+	}
+}
+
+func TestTargetPort(t *testing.T) {
+	pod := &core.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg-0"},
+		Spec: core.PodSpec{Containers: []core.Container{
+			{Name: "exporter", Ports: []core.ContainerPort{{Name: "prom-http", ContainerPort: 56790}}},
+			{Name: "postgres", Ports: []core.ContainerPort{{Name: "api", ContainerPort: 5433}}},
+		}},
+	}
+	service := func(targetPort intstr.IntOrString) *core.Service {
+		return &core.Service{Spec: core.ServiceSpec{Ports: []core.ServicePort{
+			{Port: 5432, TargetPort: targetPort},
+			{Port: 56790, TargetPort: intstr.FromString("prom-http")},
+		}}}
+	}
+
+	cases := []struct {
+		name       string
+		targetPort intstr.IntOrString
+		want       int32
+		wantErr    string
+	}{
+		{name: "port of the service", want: 5432},
+		{name: "numeric target port", targetPort: intstr.FromInt(5434), want: 5434},
+		{name: "named target port", targetPort: intstr.FromString("api"), want: 5433},
+		{name: "missing named port", targetPort: intstr.FromString("db"), wantErr: `pod "pg-0" has no port named "db"`},
+	}
+	for _, c := range cases {
+		got, err := targetPort(service(c.targetPort), pod)
+		if c.wantErr != "" {
+			if err == nil || err.Error() != c.wantErr {
+				t.Errorf("%s: targetPort() error = %v, want %s", c.name, err, c.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("%s: targetPort() = %d, %v, want %d", c.name, got, err, c.want)
+		}
+	}
+}
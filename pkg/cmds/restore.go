@@ -0,0 +1,532 @@
+package cmds
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/databases"
+)
+
+var (
+	restoreLong = templates.LongDesc(`
+		Create a database from a snapshot.
+
+		The new database is a copy of the database the snapshot was taken from, read from the live
+		database or from its DormantDatabase, initialized from the snapshot. Its backup schedule is
+		not copied. The command waits for the database to be running and reports the result of the
+		restore jobs.
+
+		The database is created in the current namespace, the snapshot is read from the namespace given
+		by --snapshot-namespace. The secrets and configmaps the database and the snapshot storage refer
+		to, e.g. the database secret and the custom configuration of the database, are copied from the
+		namespace of the snapshot unless they exist already.`)
+
+	restoreExample = templates.Examples(`
+		# Restore a snapshot into a new database named postgres-restored
+		kubedb restore snapshot/postgres-demo-20190801-120000 --as postgres-restored
+
+		# Print the database, secrets and configmaps that would be created, without creating them
+		kubedb restore snapshot/mongodb-demo-20190801-120000 --as mongodb-restored --dry-run
+
+		# Restore a snapshot of namespace demo into a new database in namespace staging
+		kubedb restore snapshot/mysql-demo-20190801-120000 --snapshot-namespace demo --as mysql-restored -n staging`)
+)
+
+type RestoreOptions struct {
+	Namespace         string
+	SnapshotNamespace string
+	SnapshotName      string
+	As                string
+
+	DryRun  bool
+	Wait    bool
+	Timeout time.Duration
+
+	Client        kubernetes.Interface
+	DynamicClient dynamic.Interface
+	KubedbClient  cs.KubedbV1alpha1Interface
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdRestore(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &RestoreOptions{
+		Wait:      true,
+		Timeout:   30 * time.Minute,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "restore (snapshot/NAME | NAME) --as NEW_NAME",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Create a database from a snapshot"),
+		Long:                  restoreLong,
+		Example:               restoreExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringVar(&o.As, "as", o.As, "Name of the database to create.")
+	cmd.Flags().StringVar(&o.SnapshotNamespace, "snapshot-namespace", o.SnapshotNamespace, "Namespace of the snapshot, defaults to the namespace of the database to create.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "If true, only print the database and the secrets and configmaps that would be created.")
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "If true, wait for the database to be running.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait, zero means wait forever.")
+	return cmd
+}
+
+func (o *RestoreOptions) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.SnapshotName, err = snapshotName(args)
+	if err != nil {
+		return err
+	}
+	if o.As == "" {
+		return fmt.Errorf("You must specify the name of the database to create with --as.")
+	}
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	if o.SnapshotNamespace == "" {
+		o.SnapshotNamespace = o.Namespace
+	}
+	o.Client, err = f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.DynamicClient, err = f.DynamicClient()
+	if err != nil {
+		return err
+	}
+	o.KubedbClient, err = newKubedbClient(f)
+	return err
+}
+
+func (o *RestoreOptions) Run() error {
+	snapshot, err := o.KubedbClient.Snapshots(o.SnapshotNamespace).Get(o.SnapshotName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if snapshot.Status.Phase != api.SnapshotPhaseSucceeded {
+		return fmt.Errorf("snapshot %q is %s, only succeeded snapshots can be restored", snapshot.Name, orUnknown(string(snapshot.Status.Phase)))
+	}
+
+	obj, gvr, err := o.restoredObject(snapshot)
+	if err != nil {
+		return err
+	}
+	secretNames, configMapNames, err := restoredDependencies(obj, snapshot)
+	if err != nil {
+		return err
+	}
+	secrets, err := o.missingSecrets(secretNames, snapshot.Namespace)
+	if err != nil {
+		return err
+	}
+	configMaps, err := o.missingConfigMaps(configMapNames, snapshot.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if o.DryRun {
+		objs := make([]interface{}, 0, len(secrets)+len(configMaps)+1)
+		for _, secret := range secrets {
+			objs = append(objs, secret)
+		}
+		for _, cm := range configMaps {
+			objs = append(objs, cm)
+		}
+		return printYAMLDocuments(o.Out, append(objs, obj.Object))
+	}
+
+	for _, secret := range secrets {
+		if _, err = o.Client.CoreV1().Secrets(o.Namespace).Create(secret); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "secret %q copied from namespace %q\n", secret.Name, snapshot.Namespace)
+	}
+	for _, cm := range configMaps {
+		if _, err = o.Client.CoreV1().ConfigMaps(o.Namespace).Create(cm); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "configmap %q copied from namespace %q\n", cm.Name, snapshot.Namespace)
+	}
+	if _, err = o.DynamicClient.Resource(gvr).Namespace(o.Namespace).Create(obj, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	kind := fmt.Sprintf("%s.%s", strings.ToLower(obj.GetKind()), gvr.Group)
+	fmt.Fprintf(o.Out, "%s %q created from snapshot %q\n", kind, obj.GetName(), snapshot.Name)
+
+	if !o.Wait {
+		return nil
+	}
+	waitErr := o.waitForRunning(gvr, obj.GetName())
+	if err = o.reportRestoreJobs(obj.GetKind(), obj.GetName()); err != nil {
+		return err
+	}
+	if waitErr != nil {
+		return waitErr
+	}
+	fmt.Fprintf(o.Out, "%s %q restored\n", kind, obj.GetName())
+	return nil
+}
+
+// restoredObject builds the database to create, from the database the snapshot was taken from.
+func (o *RestoreOptions) restoredObject(snapshot *api.Snapshot) (*unstructured.Unstructured, schema.GroupVersionResource, error) {
+	kind := snapshot.Labels[api.LabelDatabaseKind]
+	resource := databases.Kinds[kind].Resource
+	if resource == "" {
+		return nil, schema.GroupVersionResource{}, fmt.Errorf("snapshot %q has no or unknown %s label %q", snapshot.Name, api.LabelDatabaseKind, kind)
+	}
+	gvr := api.SchemeGroupVersion.WithResource(resource)
+
+	origin, err := o.DynamicClient.Resource(gvr).Namespace(snapshot.Namespace).Get(snapshot.Spec.DatabaseName, metav1.GetOptions{})
+	if kerr.IsNotFound(err) {
+		drmn, err := o.KubedbClient.DormantDatabases(snapshot.Namespace).Get(snapshot.Spec.DatabaseName, metav1.GetOptions{})
+		if kerr.IsNotFound(err) {
+			return nil, gvr, fmt.Errorf("neither %s nor dormantdatabase %q of snapshot %q found", resource, snapshot.Spec.DatabaseName, snapshot.Name)
+		} else if err != nil {
+			return nil, gvr, err
+		}
+		origin, _, err = originObject(drmn)
+		if err != nil {
+			return nil, gvr, err
+		}
+		if origin.GetKind() != kind {
+			return nil, gvr, fmt.Errorf("dormantdatabase %q is a %s, but snapshot %q is of a %s", drmn.Name, origin.GetKind(), snapshot.Name, kind)
+		}
+	} else if err != nil {
+		return nil, gvr, err
+	}
+
+	spec, _, err := unstructured.NestedMap(origin.Object, "spec")
+	if err != nil {
+		return nil, gvr, err
+	}
+	delete(spec, "backupSchedule")
+	spec["init"] = map[string]interface{}{
+		"snapshotSource": map[string]interface{}{
+			"namespace": snapshot.Namespace,
+			"name":      snapshot.Name,
+		},
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetAPIVersion(api.SchemeGroupVersion.String())
+	obj.SetKind(kind)
+	obj.SetNamespace(o.Namespace)
+	obj.SetName(o.As)
+	obj.SetLabels(userLabels(origin.GetLabels()))
+	return obj, gvr, nil
+}
+
+// restoredDependencies returns the names of the secrets and configmaps the restored database obj
+// refers to, including the storage secret of snapshot used by the restore jobs.
+func restoredDependencies(obj *unstructured.Unstructured, snapshot *api.Snapshot) ([]string, []string, error) {
+	spec, err := databaseSpec(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secrets := sets.NewString(spec.GetSecrets()...)
+	// EtcdSpec.GetSecrets does not list the database secret
+	if etcd, ok := spec.(*api.EtcdSpec); ok && etcd.DatabaseSecret != nil {
+		secrets.Insert(etcd.DatabaseSecret.SecretName)
+	}
+	if snapshot.Spec.StorageSecretName != "" {
+		secrets.Insert(snapshot.Spec.StorageSecretName)
+	}
+	configMaps := sets.NewString()
+	for _, source := range configSources(spec) {
+		switch {
+		case source == nil:
+		case source.Secret != nil:
+			secrets.Insert(source.Secret.SecretName)
+		case source.ConfigMap != nil:
+			configMaps.Insert(source.ConfigMap.Name)
+		case source.Projected != nil:
+			for _, p := range source.Projected.Sources {
+				if p.Secret != nil {
+					secrets.Insert(p.Secret.Name)
+				}
+				if p.ConfigMap != nil {
+					configMaps.Insert(p.ConfigMap.Name)
+				}
+			}
+		}
+	}
+	secrets.Delete("")
+	configMaps.Delete("")
+	return secrets.List(), configMaps.List(), nil
+}
+
+// secretsSpec is implemented by the specs of all database kinds.
+type secretsSpec interface {
+	GetSecrets() []string
+}
+
+// databaseSpec decodes the spec of the database obj into the spec type of its kind.
+func databaseSpec(obj *unstructured.Unstructured) (secretsSpec, error) {
+	var spec secretsSpec
+	switch obj.GetKind() {
+	case api.ResourceKindElasticsearch:
+		spec = &api.ElasticsearchSpec{}
+	case api.ResourceKindEtcd:
+		spec = &api.EtcdSpec{}
+	case api.ResourceKindMariaDB:
+		spec = &api.MariaDBSpec{}
+	case api.ResourceKindMemcached:
+		spec = &api.MemcachedSpec{}
+	case api.ResourceKindMongoDB:
+		spec = &api.MongoDBSpec{}
+	case api.ResourceKindMySQL:
+		spec = &api.MySQLSpec{}
+	case api.ResourceKindPerconaXtraDB:
+		spec = &api.PerconaXtraDBSpec{}
+	case api.ResourceKindPostgres:
+		spec = &api.PostgresSpec{}
+	case api.ResourceKindRedis:
+		spec = &api.RedisSpec{}
+	default:
+		return nil, fmt.Errorf("unknown database kind %q", obj.GetKind())
+	}
+
+	content, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return nil, err
+	}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// configSources returns the volume sources of the custom configuration of the database with spec.
+func configSources(spec secretsSpec) []*core.VolumeSource {
+	switch s := spec.(type) {
+	case *api.ElasticsearchSpec:
+		return []*core.VolumeSource{s.ConfigSource}
+	case *api.MariaDBSpec:
+		return []*core.VolumeSource{s.ConfigSource}
+	case *api.MemcachedSpec:
+		return []*core.VolumeSource{s.ConfigSource}
+	case *api.MongoDBSpec:
+		sources := []*core.VolumeSource{s.ConfigSource}
+		if t := s.ShardTopology; t != nil {
+			sources = append(sources, t.Shard.ConfigSource, t.ConfigServer.ConfigSource, t.Mongos.ConfigSource)
+		}
+		return sources
+	case *api.MySQLSpec:
+		return []*core.VolumeSource{s.ConfigSource}
+	case *api.PerconaXtraDBSpec:
+		return []*core.VolumeSource{s.ConfigSource}
+	case *api.PostgresSpec:
+		return []*core.VolumeSource{s.ConfigSource}
+	case *api.RedisSpec:
+		return []*core.VolumeSource{s.ConfigSource}
+	}
+	return nil
+}
+
+// missingSecrets returns copies of the secrets that are missing in the namespace of the new database,
+// read from namespace. It fails if a secret does not exist in either namespace.
+func (o *RestoreOptions) missingSecrets(names []string, namespace string) ([]*core.Secret, error) {
+	var missing []*core.Secret
+	for _, name := range names {
+		_, err := o.Client.CoreV1().Secrets(o.Namespace).Get(name, metav1.GetOptions{})
+		if err == nil {
+			continue
+		} else if !kerr.IsNotFound(err) {
+			return nil, err
+		}
+		if namespace == o.Namespace {
+			return nil, fmt.Errorf("secret %q not found in namespace %q", name, o.Namespace)
+		}
+
+		secret, err := o.Client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+		if kerr.IsNotFound(err) {
+			return nil, fmt.Errorf("secret %q found in neither namespace %q nor %q", name, o.Namespace, namespace)
+		} else if err != nil {
+			return nil, err
+		}
+		missing = append(missing, &core.Secret{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secret.Name,
+				Namespace: o.Namespace,
+				Labels:    userLabels(secret.Labels),
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		})
+	}
+	return missing, nil
+}
+
+// missingConfigMaps returns copies of the configmaps that are missing in the namespace of the new database,
+// read from namespace. It fails if a configmap does not exist in either namespace.
+func (o *RestoreOptions) missingConfigMaps(names []string, namespace string) ([]*core.ConfigMap, error) {
+	var missing []*core.ConfigMap
+	for _, name := range names {
+		_, err := o.Client.CoreV1().ConfigMaps(o.Namespace).Get(name, metav1.GetOptions{})
+		if err == nil {
+			continue
+		} else if !kerr.IsNotFound(err) {
+			return nil, err
+		}
+		if namespace == o.Namespace {
+			return nil, fmt.Errorf("configmap %q not found in namespace %q", name, o.Namespace)
+		}
+
+		cm, err := o.Client.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+		if kerr.IsNotFound(err) {
+			return nil, fmt.Errorf("configmap %q found in neither namespace %q nor %q", name, o.Namespace, namespace)
+		} else if err != nil {
+			return nil, err
+		}
+		missing = append(missing, &core.ConfigMap{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cm.Name,
+				Namespace: o.Namespace,
+				Labels:    userLabels(cm.Labels),
+			},
+			Data:       cm.Data,
+			BinaryData: cm.BinaryData,
+		})
+	}
+	return missing, nil
+}
+
+// waitForRunning waits for the restored database to be initialized and running. It fails early if the database fails.
+func (o *RestoreOptions) waitForRunning(gvr schema.GroupVersionResource, name string) error {
+	var phase string
+	err := poll(o.Timeout, func() (bool, error) {
+		db, err := o.DynamicClient.Resource(gvr).Namespace(o.Namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		p, _, _ := unstructured.NestedString(db.Object, "status", "phase")
+		if p != phase {
+			phase = p
+			fmt.Fprintf(o.Out, "%s %q is %s\n", gvr.Resource, name, orUnknown(phase))
+		}
+		if phase == string(api.DatabasePhaseFailed) {
+			reason, _, _ := unstructured.NestedString(db.Object, "status", "reason")
+			return false, fmt.Errorf("%s %q failed: %s", gvr.Resource, name, orUnknown(reason))
+		}
+		return phase == string(api.DatabasePhaseRunning), nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for %s %q to be running", gvr.Resource, name)
+	}
+	return err
+}
+
+// reportRestoreJobs prints whether the restore jobs of the database succeeded.
+func (o *RestoreOptions) reportRestoreJobs(kind, name string) error {
+	selector := labels.SelectorFromSet(map[string]string{
+		api.LabelDatabaseKind: kind,
+		api.LabelDatabaseName: name,
+	})
+	jobs, err := o.Client.BatchV1().Jobs(o.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, job := range jobs.Items {
+		if job.Labels[api.AnnotationJobType] != api.JobTypeRestore && job.Annotations[api.AnnotationJobType] != api.JobTypeRestore {
+			continue
+		}
+		found = true
+		switch {
+		case job.Status.Succeeded > 0:
+			fmt.Fprintf(o.Out, "restore job %q succeeded\n", job.Name)
+		case job.Status.Failed > 0:
+			fmt.Fprintf(o.Out, "restore job %q failed %d time(s)\n", job.Name, job.Status.Failed)
+		default:
+			fmt.Fprintf(o.Out, "restore job %q is running\n", job.Name)
+		}
+	}
+	if !found {
+		fmt.Fprintf(o.Out, "no restore jobs found\n")
+	}
+	return nil
+}
+
+// printYAMLDocuments prints objs as a stream of YAML documents.
+func printYAMLDocuments(out io.Writer, objs []interface{}) error {
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(out, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotName returns the name of the Snapshot given as NAME, snapshot/NAME or snapshot NAME.
+func snapshotName(args []string) (string, error) {
+	var resourceType, name string
+	switch len(args) {
+	case 1:
+		parts := strings.SplitN(args[0], "/", 2)
+		if len(parts) == 1 {
+			return parts[0], nil
+		}
+		resourceType, name = parts[0], parts[1]
+	case 2:
+		resourceType, name = args[0], args[1]
+	default:
+		return "", fmt.Errorf("You must specify the snapshot to restore.")
+	}
+
+	switch strings.ToLower(resourceType) {
+	case api.ResourceCodeSnapshot,
+		api.ResourceSingularSnapshot,
+		api.ResourcePluralSnapshot,
+		api.ResourceSingularSnapshot + "." + api.SchemeGroupVersion.Group,
+		api.ResourcePluralSnapshot + "." + api.SchemeGroupVersion.Group:
+		return name, nil
+	}
+	return "", fmt.Errorf("only snapshots can be restored, found %q", resourceType)
+}
+
+// userLabels returns the labels that are not managed by KubeDB.
+func userLabels(in map[string]string) map[string]string {
+	out := map[string]string{}
+	for k, v := range in {
+		if k == api.GenericKey || strings.HasPrefix(k, api.GenericKey+"/") || strings.HasSuffix(k, "."+api.GenericKey) {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
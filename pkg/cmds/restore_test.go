@@ -0,0 +1,215 @@
+package cmds
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	store "kmodules.xyz/objectstore-api/api/v1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/internal/fakeapi"
+)
+
+func restoredDatabase(t *testing.T, kind string, spec interface{}) *unstructured.Unstructured {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": content}}
+	obj.SetAPIVersion(api.SchemeGroupVersion.String())
+	obj.SetKind(kind)
+	return obj
+}
+
+func TestRestoredDependencies(t *testing.T) {
+	snapshot := &api.Snapshot{Spec: api.SnapshotSpec{Backend: store.Backend{StorageSecretName: "gcs-secret"}}}
+
+	cases := []struct {
+		name           string
+		kind           string
+		spec           interface{}
+		wantSecrets    []string
+		wantConfigMaps []string
+	}{
+		{
+			name: "postgres",
+			kind: api.ResourceKindPostgres,
+			spec: &api.PostgresSpec{
+				DatabaseSecret: &core.SecretVolumeSource{SecretName: "pg-auth"},
+				ConfigSource:   &core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: "pg-config"}}},
+			},
+			wantSecrets:    []string{"gcs-secret", "pg-auth"},
+			wantConfigMaps: []string{"pg-config"},
+		},
+		{
+			name: "sharded mongodb",
+			kind: api.ResourceKindMongoDB,
+			spec: &api.MongoDBSpec{
+				DatabaseSecret:    &core.SecretVolumeSource{SecretName: "mg-auth"},
+				CertificateSecret: &core.SecretVolumeSource{SecretName: "mg-keyfile"},
+				ShardTopology: &api.MongoDBShardingTopology{
+					Shard: api.MongoDBShardNode{MongoDBNode: api.MongoDBNode{
+						ConfigSource: &core.VolumeSource{Secret: &core.SecretVolumeSource{SecretName: "mg-shard-config"}},
+					}},
+					Mongos: api.MongoDBMongosNode{MongoDBNode: api.MongoDBNode{
+						ConfigSource: &core.VolumeSource{Projected: &core.ProjectedVolumeSource{Sources: []core.VolumeProjection{
+							{ConfigMap: &core.ConfigMapProjection{LocalObjectReference: core.LocalObjectReference{Name: "mg-mongos-config"}}},
+							{Secret: &core.SecretProjection{LocalObjectReference: core.LocalObjectReference{Name: "mg-mongos-secret"}}},
+						}}},
+					}},
+				},
+			},
+			wantSecrets:    []string{"gcs-secret", "mg-auth", "mg-keyfile", "mg-mongos-secret", "mg-shard-config"},
+			wantConfigMaps: []string{"mg-mongos-config"},
+		},
+		{
+			name:        "etcd",
+			kind:        api.ResourceKindEtcd,
+			spec:        &api.EtcdSpec{DatabaseSecret: &core.SecretVolumeSource{SecretName: "etcd-auth"}},
+			wantSecrets: []string{"etcd-auth", "gcs-secret"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			secrets, configMaps, err := restoredDependencies(restoredDatabase(t, c.kind, c.spec), snapshot)
+			if err != nil {
+				t.Fatalf("restoredDependencies() error = %v", err)
+			}
+			if !reflect.DeepEqual(secrets, c.wantSecrets) {
+				t.Errorf("restoredDependencies() secrets = %v, want %v", secrets, c.wantSecrets)
+			}
+			if len(configMaps) != 0 || len(c.wantConfigMaps) != 0 {
+				if !reflect.DeepEqual(configMaps, c.wantConfigMaps) {
+					t.Errorf("restoredDependencies() configmaps = %v, want %v", configMaps, c.wantConfigMaps)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreDryRun(t *testing.T) {
+	s := fakeapi.NewServer(t)
+	s.Add("/apis/kubedb.com/v1alpha1", api.ResourcePluralSnapshot, &api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pg-snap",
+			Namespace: "demo",
+			Labels:    map[string]string{api.LabelDatabaseKind: api.ResourceKindPostgres, api.LabelDatabaseName: "pg"},
+		},
+		Spec: api.SnapshotSpec{
+			DatabaseName: "pg",
+			Backend:      store.Backend{StorageSecretName: "gcs-secret", GCS: &store.GCSSpec{Bucket: "backups"}},
+		},
+		Status: api.SnapshotStatus{Phase: api.SnapshotPhaseSucceeded},
+	})
+	s.Add("/apis/kubedb.com/v1alpha1", api.ResourcePluralPostgres, &api.Postgres{
+		TypeMeta:   metav1.TypeMeta{APIVersion: api.SchemeGroupVersion.String(), Kind: api.ResourceKindPostgres},
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "demo", Labels: map[string]string{"team": "a"}},
+		Spec: api.PostgresSpec{
+			Version:        "10.2-v2",
+			DatabaseSecret: &core.SecretVolumeSource{SecretName: "pg-auth"},
+			ConfigSource:   &core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: "pg-config"}}},
+		},
+	})
+	s.Add("/api/v1", "secrets",
+		&core.Secret{ObjectMeta: metav1.ObjectMeta{Name: "pg-auth", Namespace: "demo"}, Data: map[string][]byte{"POSTGRES_USER": []byte("postgres")}},
+		&core.Secret{ObjectMeta: metav1.ObjectMeta{Name: "gcs-secret", Namespace: "demo"}},
+		// exists in the namespace of the restored database already, so it is not copied
+		&core.Secret{ObjectMeta: metav1.ObjectMeta{Name: "gcs-secret", Namespace: "staging"}},
+	)
+	s.Add("/api/v1", "configmaps",
+		&core.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "pg-config", Namespace: "demo"}, Data: map[string]string{"user.conf": "max_connections=300"}},
+	)
+
+	client, kubedbClient := testClients(t, s)
+	dynamicClient, err := dynamic.NewForConfig(s.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	o := &RestoreOptions{
+		Namespace:         "staging",
+		SnapshotNamespace: "demo",
+		SnapshotName:      "pg-snap",
+		As:                "pg-restored",
+		DryRun:            true,
+		Client:            client,
+		DynamicClient:     dynamicClient,
+		KubedbClient:      kubedbClient,
+		IOStreams:         genericclioptions.IOStreams{Out: out},
+	}
+	// the server serves reads only, so creating an object fails the dry run
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := `---
+apiVersion: v1
+data:
+  POSTGRES_USER: cG9zdGdyZXM=
+kind: Secret
+metadata:
+  creationTimestamp: null
+  name: pg-auth
+  namespace: staging
+---
+apiVersion: v1
+data:
+  user.conf: max_connections=300
+kind: ConfigMap
+metadata:
+  creationTimestamp: null
+  name: pg-config
+  namespace: staging
+---
+apiVersion: kubedb.com/v1alpha1
+kind: Postgres
+metadata:
+  labels:
+    team: a
+  name: pg-restored
+  namespace: staging
+spec:
+  configSource:
+    configMap:
+      name: pg-config
+  databaseSecret:
+    secretName: pg-auth
+  init:
+    snapshotSource:
+      name: pg-snap
+      namespace: demo
+  podTemplate:
+    controller: {}
+    metadata: {}
+    spec:
+      resources: {}
+  replicaServiceTemplate:
+    metadata: {}
+    spec: {}
+  serviceTemplate:
+    metadata: {}
+    spec: {}
+  updateStrategy: {}
+  version: 10.2-v2
+`
+	if out.String() != want {
+		t.Errorf("Run() printed\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRestoreMissingConfigMap(t *testing.T) {
+	s := fakeapi.NewServer(t)
+	client, _ := testClients(t, s)
+	o := &RestoreOptions{Namespace: "staging", Client: client}
+	_, err := o.missingConfigMaps([]string{"pg-config"}, "demo")
+	want := `configmap "pg-config" found in neither namespace "staging" nor "demo"`
+	if err == nil || err.Error() != want {
+		t.Errorf("missingConfigMaps() error = %v, want %s", err, want)
+	}
+}
@@ -0,0 +1,228 @@
+package cmds
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/databases"
+)
+
+var (
+	resumeLong = templates.LongDesc(`
+		Resume a paused database.
+
+		The database is created again from the spec, labels and annotations stored in its
+		DormantDatabase. The KubeDB operator then resumes it using the data kept while it was paused.`)
+
+	resumeExample = templates.Examples(`
+		# Resume a paused database
+		kubedb resume drmn/postgres-demo
+
+		# Resume a paused database without waiting for it to be running
+		kubedb resume postgres-demo --wait=false`)
+)
+
+type ResumeOptions struct {
+	Namespace string
+	Name      string
+
+	Wait    bool
+	Timeout time.Duration
+
+	DynamicClient dynamic.Interface
+	KubedbClient  cs.KubedbV1alpha1Interface
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdResume(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &ResumeOptions{
+		Wait:      true,
+		Timeout:   10 * time.Minute,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "resume (NAME | drmn/NAME)",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Resume a paused database from its DormantDatabase"),
+		Long:                  resumeLong,
+		Example:               resumeExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "If true, wait for the database to be running.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait, zero means wait forever.")
+	return cmd
+}
+
+func (o *ResumeOptions) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.Name, err = dormantDatabaseName(args)
+	if err != nil {
+		return err
+	}
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	o.DynamicClient, err = f.DynamicClient()
+	if err != nil {
+		return err
+	}
+	o.KubedbClient, err = newKubedbClient(f)
+	return err
+}
+
+func (o *ResumeOptions) Run() error {
+	drmn, err := o.KubedbClient.DormantDatabases(o.Namespace).Get(o.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if drmn.Status.Phase != api.DormantDatabasePhasePaused {
+		return fmt.Errorf("dormantdatabase %q is %s, only paused databases can be resumed", drmn.Name, orUnknown(string(drmn.Status.Phase)))
+	}
+
+	obj, gvr, err := originObject(drmn)
+	if err != nil {
+		return err
+	}
+	if _, err = o.DynamicClient.Resource(gvr).Namespace(o.Namespace).Create(obj, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	kind := fmt.Sprintf("%s.%s", strings.ToLower(obj.GetKind()), gvr.Group)
+	fmt.Fprintf(o.Out, "%s %q resuming\n", kind, obj.GetName())
+
+	if !o.Wait {
+		return nil
+	}
+	if err = o.waitForRunning(gvr, obj.GetName()); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "%s %q resumed\n", kind, obj.GetName())
+	return nil
+}
+
+// waitForRunning waits for the resumed database to be running. It fails early if the database fails.
+func (o *ResumeOptions) waitForRunning(gvr schema.GroupVersionResource, name string) error {
+	err := poll(o.Timeout, func() (bool, error) {
+		db, err := o.DynamicClient.Resource(gvr).Namespace(o.Namespace).Get(name, metav1.GetOptions{})
+		if kerr.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		phase, _, _ := unstructured.NestedString(db.Object, "status", "phase")
+		if phase == string(api.DatabasePhaseFailed) {
+			reason, _, _ := unstructured.NestedString(db.Object, "status", "reason")
+			return false, fmt.Errorf("%s %q failed: %s", gvr.Resource, name, orUnknown(reason))
+		}
+		return phase == string(api.DatabasePhaseRunning), nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for %s %q to be running", gvr.Resource, name)
+	}
+	return err
+}
+
+// dormantDatabaseName returns the name of the DormantDatabase given as NAME, drmn/NAME or drmn NAME.
+func dormantDatabaseName(args []string) (string, error) {
+	switch len(args) {
+	case 1:
+		parts := strings.SplitN(args[0], "/", 2)
+		if len(parts) == 1 {
+			return parts[0], nil
+		}
+		if !isDormantDatabaseType(parts[0]) {
+			return "", fmt.Errorf("only dormantdatabases can be resumed, found %q", parts[0])
+		}
+		return parts[1], nil
+	case 2:
+		if !isDormantDatabaseType(args[0]) {
+			return "", fmt.Errorf("only dormantdatabases can be resumed, found %q", args[0])
+		}
+		return args[1], nil
+	}
+	return "", fmt.Errorf("You must specify the dormantdatabase to resume.")
+}
+
+func isDormantDatabaseType(resourceType string) bool {
+	switch strings.ToLower(resourceType) {
+	case api.ResourceCodeDormantDatabase,
+		api.ResourceSingularDormantDatabase,
+		api.ResourcePluralDormantDatabase,
+		api.ResourceSingularDormantDatabase + "." + api.SchemeGroupVersion.Group,
+		api.ResourcePluralDormantDatabase + "." + api.SchemeGroupVersion.Group:
+		return true
+	}
+	return false
+}
+
+// originObject builds the database stored in the origin of a DormantDatabase.
+func originObject(drmn *api.DormantDatabase) (*unstructured.Unstructured, schema.GroupVersionResource, error) {
+	var (
+		kind string
+		spec interface{}
+	)
+	origin := drmn.Spec.Origin.Spec
+	switch {
+	case origin.Elasticsearch != nil:
+		kind, spec = api.ResourceKindElasticsearch, origin.Elasticsearch
+	case origin.Etcd != nil:
+		kind, spec = api.ResourceKindEtcd, origin.Etcd
+	case origin.MariaDB != nil:
+		kind, spec = api.ResourceKindMariaDB, origin.MariaDB
+	case origin.Memcached != nil:
+		kind, spec = api.ResourceKindMemcached, origin.Memcached
+	case origin.MongoDB != nil:
+		kind, spec = api.ResourceKindMongoDB, origin.MongoDB
+	case origin.MySQL != nil:
+		kind, spec = api.ResourceKindMySQL, origin.MySQL
+	case origin.PerconaXtraDB != nil:
+		kind, spec = api.ResourceKindPerconaXtraDB, origin.PerconaXtraDB
+	case origin.Postgres != nil:
+		kind, spec = api.ResourceKindPostgres, origin.Postgres
+	case origin.Redis != nil:
+		kind, spec = api.ResourceKindRedis, origin.Redis
+	default:
+		return nil, schema.GroupVersionResource{}, fmt.Errorf("dormantdatabase %q has no origin spec", drmn.Name)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return nil, schema.GroupVersionResource{}, err
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": content}}
+	obj.SetAPIVersion(api.SchemeGroupVersion.String())
+	obj.SetKind(kind)
+	obj.SetNamespace(drmn.Namespace)
+	obj.SetName(drmn.Name)
+	obj.SetLabels(drmn.Spec.Origin.Labels)
+	obj.SetAnnotations(drmn.Spec.Origin.Annotations)
+	return obj, api.SchemeGroupVersion.WithResource(databases.Kinds[kind].Resource), nil
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "<unknown>"
+	}
+	return s
+}
@@ -64,10 +64,23 @@ func NewKubeDBCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 				NewCmdDelete(f, ioStreams),
 			},
 		},
+		{
+			Message: "Database Management Commands:",
+			Commands: []*cobra.Command{
+				NewCmdPause(f, ioStreams),
+				NewCmdResume(f, ioStreams),
+				NewCmdWipeOut(f, ioStreams),
+				NewCmdBackup(f, ioStreams),
+				NewCmdRestore(f, ioStreams),
+				NewCmdSnapshot(f, ioStreams),
+			},
+		},
 		{
 			Message: "Troubleshooting and Debugging Commands:",
 			Commands: []*cobra.Command{
 				NewCmdDescribe("kubedb", f, ioStreams),
+				NewCmdConnect(f, ioStreams),
+				NewCmdPortForward(f, ioStreams),
 				NewCmdApiResources(f, ioStreams),
 				v.NewCmdVersion(),
 			},
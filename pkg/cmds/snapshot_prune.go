@@ -0,0 +1,316 @@
+package cmds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/printers"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
+)
+
+var (
+	snapshotPruneLong = templates.LongDesc(`
+		Delete the snapshots of databases that are not kept by any retention rule.
+
+		Retention rules are applied to the succeeded snapshots of each database separately, ordered
+		by completion time. A snapshot is kept if any rule keeps it. --max-age is applied after the
+		keep rules as a hard limit: older snapshots are deleted even if a rule keeps them. Given alone,
+		it keeps all snapshots within the age. Snapshots that are running, have failed or have no
+		completion time are never pruned by retention rules; failed snapshots can be deleted with
+		--failed-only instead.
+
+		The snapshots to delete are listed before anything is done, and the command asks for
+		confirmation unless --yes is given. Deleting a snapshot deletes its backup data.`)
+
+	snapshotPruneExample = templates.Examples(`
+		# Keep the last 3 snapshots and one snapshot for each of the last 7 days of every database
+		kubedb snapshot prune --keep-last=3 --keep-daily=7
+
+		# Show which snapshots of postgres-demo older than 30 days would be deleted
+		kubedb snapshot prune postgres-demo --max-age=720h --dry-run
+
+		# Delete the failed snapshots of all databases
+		kubedb snapshot prune --failed-only --yes`)
+)
+
+type SnapshotPruneOptions struct {
+	Namespace string
+	Databases []string
+	Selector  string
+
+	KeepLast    int
+	KeepDaily   int
+	KeepWeekly  int
+	KeepMonthly int
+	MaxAge      time.Duration
+	FailedOnly  bool
+
+	DryRun bool
+	Yes    bool
+
+	KubedbClient cs.KubedbV1alpha1Interface
+
+	genericclioptions.IOStreams
+}
+
+// snapshotPlan is the decision taken for a single snapshot.
+type snapshotPlan struct {
+	snapshot api.Snapshot
+	database string
+	delete   bool
+	reason   string
+}
+
+func NewCmdSnapshotPrune(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &SnapshotPruneOptions{
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "prune [DATABASE_NAME...]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Delete the snapshots of databases according to retention rules"),
+		Long:                  snapshotPruneLong,
+		Example:               snapshotPruneExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringVarP(&o.Selector, "selector", "l", o.Selector, "Selector (label query) to filter snapshots on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().IntVar(&o.KeepLast, "keep-last", o.KeepLast, "Keep the last N snapshots of each database.")
+	cmd.Flags().IntVar(&o.KeepDaily, "keep-daily", o.KeepDaily, "Keep the last snapshot of each of the last N days with snapshots.")
+	cmd.Flags().IntVar(&o.KeepWeekly, "keep-weekly", o.KeepWeekly, "Keep the last snapshot of each of the last N weeks with snapshots.")
+	cmd.Flags().IntVar(&o.KeepMonthly, "keep-monthly", o.KeepMonthly, "Keep the last snapshot of each of the last N months with snapshots.")
+	cmd.Flags().DurationVar(&o.MaxAge, "max-age", o.MaxAge, "Delete the snapshots completed longer ago than this duration, i.e. 720h, even if other rules keep them.")
+	cmd.Flags().BoolVar(&o.FailedOnly, "failed-only", o.FailedOnly, "If true, delete the failed snapshots instead of applying retention rules.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "If true, only print the snapshots that would be deleted.")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", o.Yes, "If true, do not ask for confirmation.")
+	return cmd
+}
+
+func (o *SnapshotPruneOptions) Complete(f cmdutil.Factory, args []string) error {
+	o.Databases = args
+
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	o.KubedbClient, err = newKubedbClient(f)
+	return err
+}
+
+func (o *SnapshotPruneOptions) Validate() error {
+	if o.KeepLast < 0 || o.KeepDaily < 0 || o.KeepWeekly < 0 || o.KeepMonthly < 0 || o.MaxAge < 0 {
+		return fmt.Errorf("retention rules can not be negative")
+	}
+	hasRules := o.KeepLast > 0 || o.KeepDaily > 0 || o.KeepWeekly > 0 || o.KeepMonthly > 0 || o.MaxAge > 0
+	if o.FailedOnly && hasRules {
+		return fmt.Errorf("--failed-only can not be combined with retention rules")
+	}
+	if !o.FailedOnly && !hasRules {
+		return fmt.Errorf("You must specify at least one of --keep-last, --keep-daily, --keep-weekly, --keep-monthly, --max-age or --failed-only.")
+	}
+	return nil
+}
+
+func (o *SnapshotPruneOptions) Run() error {
+	list, err := o.KubedbClient.Snapshots(o.Namespace).List(metav1.ListOptions{LabelSelector: o.Selector})
+	if err != nil {
+		return err
+	}
+
+	var plans []snapshotPlan
+	for database, snapshots := range groupSnapshots(list.Items) {
+		if len(o.Databases) > 0 && !containsString(o.Databases, database.name) {
+			continue
+		}
+		if o.FailedOnly {
+			plans = append(plans, planFailedSnapshots(database.String(), snapshots)...)
+		} else {
+			plans = append(plans, o.planRetention(database.String(), snapshots, time.Now())...)
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].database != plans[j].database {
+			return plans[i].database < plans[j].database
+		}
+		return plans[i].snapshot.CreationTimestamp.After(plans[j].snapshot.CreationTimestamp.Time)
+	})
+
+	var deletes []snapshotPlan
+	for _, p := range plans {
+		if p.delete {
+			deletes = append(deletes, p)
+		}
+	}
+	if len(plans) == 0 {
+		fmt.Fprintf(o.Out, "No resources found\n")
+		return nil
+	}
+	printSnapshotPlans(o.IOStreams, plans)
+	if len(deletes) == 0 {
+		fmt.Fprintf(o.Out, "Nothing to prune\n")
+		return nil
+	}
+	if o.DryRun {
+		return nil
+	}
+	if !o.Yes && !confirm(o.IOStreams, fmt.Sprintf("Delete %d snapshot(s) along with their backup data?", len(deletes))) {
+		fmt.Fprintf(o.Out, "Aborted\n")
+		return nil
+	}
+
+	var errs []error
+	for _, p := range deletes {
+		if err := o.KubedbClient.Snapshots(p.snapshot.Namespace).Delete(p.snapshot.Name, &metav1.DeleteOptions{}); err != nil {
+			fmt.Fprintf(o.Out, "snapshot %q failed: %v\n", p.snapshot.Name, err)
+			errs = append(errs, fmt.Errorf("snapshot %q: %v", p.snapshot.Name, err))
+			continue
+		}
+		fmt.Fprintf(o.Out, "snapshot %q deleted\n", p.snapshot.Name)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// snapshotDatabase identifies the database a snapshot was taken from.
+type snapshotDatabase struct {
+	kind string
+	name string
+}
+
+func (d snapshotDatabase) String() string {
+	if d.kind == "" {
+		return d.name
+	}
+	return d.kind + "/" + d.name
+}
+
+// groupSnapshots groups the snapshots by the database they were taken from, identified by
+// its kind and name labels.
+func groupSnapshots(snapshots []api.Snapshot) map[snapshotDatabase][]api.Snapshot {
+	groups := map[snapshotDatabase][]api.Snapshot{}
+	for _, s := range snapshots {
+		db := snapshotDatabase{kind: s.Labels[api.LabelDatabaseKind], name: s.Labels[api.LabelDatabaseName]}
+		if db.name == "" {
+			db.name = s.Spec.DatabaseName
+		}
+		groups[db] = append(groups[db], s)
+	}
+	return groups
+}
+
+func planFailedSnapshots(database string, snapshots []api.Snapshot) []snapshotPlan {
+	var plans []snapshotPlan
+	for _, s := range snapshots {
+		if s.Status.Phase == api.SnapshotPhaseFailed {
+			plans = append(plans, snapshotPlan{snapshot: s, database: database, delete: true, reason: "failed"})
+		}
+	}
+	return plans
+}
+
+// planRetention applies the retention rules to the snapshots of a database.
+func (o *SnapshotPruneOptions) planRetention(database string, snapshots []api.Snapshot, now time.Time) []snapshotPlan {
+	var completed []api.Snapshot
+	var plans []snapshotPlan
+	for _, s := range snapshots {
+		switch {
+		case s.Status.Phase != api.SnapshotPhaseSucceeded:
+			plans = append(plans, snapshotPlan{snapshot: s, database: database, reason: strings.ToLower(orUnknown(string(s.Status.Phase)))})
+		case s.Status.CompletionTime == nil:
+			plans = append(plans, snapshotPlan{snapshot: s, database: database, reason: "no completion time"})
+		default:
+			completed = append(completed, s)
+		}
+	}
+	// newest first; snapshots completed at the same time are ordered by name, so that the
+	// same one is kept on every run
+	sort.Slice(completed, func(i, j int) bool {
+		ti, tj := completed[i].Status.CompletionTime, completed[j].Status.CompletionTime
+		if !ti.Equal(tj) {
+			return ti.After(tj.Time)
+		}
+		return completed[i].Name > completed[j].Name
+	})
+
+	rules := []struct {
+		name   string
+		keep   int
+		bucket func(api.Snapshot) string
+	}{
+		{"last", o.KeepLast, func(s api.Snapshot) string { return s.Name }},
+		{"daily", o.KeepDaily, func(s api.Snapshot) string { return s.Status.CompletionTime.Format("2006-01-02") }},
+		{"weekly", o.KeepWeekly, func(s api.Snapshot) string {
+			year, week := s.Status.CompletionTime.ISOWeek()
+			return fmt.Sprintf("%d-%d", year, week)
+		}},
+		{"monthly", o.KeepMonthly, func(s api.Snapshot) string { return s.Status.CompletionTime.Format("2006-01") }},
+	}
+
+	reasons := make([][]string, len(completed))
+	for _, rule := range rules {
+		seen := map[string]bool{}
+		for i, s := range completed {
+			if len(seen) >= rule.keep {
+				break
+			}
+			bucket := rule.bucket(s)
+			if seen[bucket] {
+				continue
+			}
+			seen[bucket] = true
+			reasons[i] = append(reasons[i], rule.name)
+		}
+	}
+	hasKeepRules := o.KeepLast > 0 || o.KeepDaily > 0 || o.KeepWeekly > 0 || o.KeepMonthly > 0
+
+	for i, s := range completed {
+		p := snapshotPlan{snapshot: s, database: database}
+		switch {
+		case o.MaxAge > 0 && now.Sub(s.Status.CompletionTime.Time) > o.MaxAge:
+			// max-age is a hard limit that overrides the keep rules
+			p.delete = true
+			p.reason = "older than max-age"
+		case len(reasons[i]) > 0:
+			p.reason = "keep " + strings.Join(reasons[i], ",")
+		case !hasKeepRules:
+			p.reason = "within max-age"
+		default:
+			p.delete = true
+			p.reason = "not retained"
+		}
+		plans = append(plans, p)
+	}
+	return plans
+}
+
+func printSnapshotPlans(streams genericclioptions.IOStreams, plans []snapshotPlan) {
+	w := printers.GetNewTabWriter(streams.Out)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "DATABASE\tSNAPSHOT\tCOMPLETED\tACTION\tREASON\n")
+	for _, p := range plans {
+		completed := "<none>"
+		if p.snapshot.Status.CompletionTime != nil {
+			completed = p.snapshot.Status.CompletionTime.UTC().Format(time.RFC3339)
+		}
+		action := "keep"
+		if p.delete {
+			action = "delete"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.database, p.snapshot.Name, completed, action, p.reason)
+	}
+}
@@ -0,0 +1,279 @@
+package cmds
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+var pruneNow = time.Date(2019, time.August, 15, 12, 0, 0, 0, time.UTC)
+
+func completedSnapshot(name string, completed time.Time) api.Snapshot {
+	t := metav1.NewTime(completed)
+	return api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: api.SnapshotStatus{
+			Phase:          api.SnapshotPhaseSucceeded,
+			CompletionTime: &t,
+		},
+	}
+}
+
+// planActions maps the names of the planned snapshots to their action and reason.
+func planActions(plans []snapshotPlan) map[string]string {
+	actions := map[string]string{}
+	for _, p := range plans {
+		action := "keep"
+		if p.delete {
+			action = "delete"
+		}
+		actions[p.snapshot.Name] = action + ": " + p.reason
+	}
+	return actions
+}
+
+func TestPlanRetention(t *testing.T) {
+	day := func(d, h int) time.Time {
+		return time.Date(2019, time.August, d, h, 0, 0, 0, time.UTC)
+	}
+	running := api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "running"},
+		Status:     api.SnapshotStatus{Phase: api.SnapshotPhaseRunning},
+	}
+	failed := api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "failed"},
+		Status:     api.SnapshotStatus{Phase: api.SnapshotPhaseFailed},
+	}
+	noCompletion := api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-completion"},
+		Status:     api.SnapshotStatus{Phase: api.SnapshotPhaseSucceeded},
+	}
+
+	cases := []struct {
+		name      string
+		options   SnapshotPruneOptions
+		snapshots []api.Snapshot
+		want      map[string]string
+	}{
+		{
+			name:    "keep last",
+			options: SnapshotPruneOptions{KeepLast: 2},
+			snapshots: []api.Snapshot{
+				completedSnapshot("s1", day(11, 1)),
+				completedSnapshot("s3", day(13, 1)),
+				completedSnapshot("s2", day(12, 1)),
+			},
+			want: map[string]string{
+				"s3": "keep: keep last",
+				"s2": "keep: keep last",
+				"s1": "delete: not retained",
+			},
+		},
+		{
+			name:    "keep last larger than the number of snapshots",
+			options: SnapshotPruneOptions{KeepLast: 5},
+			snapshots: []api.Snapshot{
+				completedSnapshot("s1", day(11, 1)),
+				completedSnapshot("s2", day(12, 1)),
+			},
+			want: map[string]string{
+				"s2": "keep: keep last",
+				"s1": "keep: keep last",
+			},
+		},
+		{
+			name:    "daily keeps the last snapshot of each day",
+			options: SnapshotPruneOptions{KeepDaily: 2},
+			snapshots: []api.Snapshot{
+				completedSnapshot("d14-late", day(14, 23)),
+				completedSnapshot("d14-early", day(14, 1)),
+				completedSnapshot("d13-late", time.Date(2019, time.August, 13, 23, 59, 59, 0, time.UTC)),
+				completedSnapshot("d13-early", time.Date(2019, time.August, 13, 0, 0, 0, 0, time.UTC)),
+				completedSnapshot("d12", day(12, 12)),
+			},
+			want: map[string]string{
+				"d14-late":  "keep: keep daily",
+				"d14-early": "delete: not retained",
+				"d13-late":  "keep: keep daily",
+				"d13-early": "delete: not retained",
+				"d12":       "delete: not retained",
+			},
+		},
+		{
+			name:    "ties on the same time keep the same snapshot",
+			options: SnapshotPruneOptions{KeepDaily: 1},
+			snapshots: []api.Snapshot{
+				completedSnapshot("a", day(14, 1)),
+				completedSnapshot("c", day(14, 1)),
+				completedSnapshot("b", day(14, 1)),
+			},
+			want: map[string]string{
+				"c": "keep: keep daily",
+				"b": "delete: not retained",
+				"a": "delete: not retained",
+			},
+		},
+		{
+			name:    "weekly buckets by ISO week",
+			options: SnapshotPruneOptions{KeepWeekly: 2},
+			snapshots: []api.Snapshot{
+				// 2019-08-12 is a Monday, 2019-08-11 the Sunday before
+				completedSnapshot("mon", day(12, 0)),
+				completedSnapshot("sun", day(11, 23)),
+				completedSnapshot("sat", day(10, 12)),
+				completedSnapshot("prev-week", day(4, 12)),
+			},
+			want: map[string]string{
+				"mon":       "keep: keep weekly",
+				"sun":       "keep: keep weekly",
+				"sat":       "delete: not retained",
+				"prev-week": "delete: not retained",
+			},
+		},
+		{
+			name:    "monthly buckets by calendar month",
+			options: SnapshotPruneOptions{KeepMonthly: 2},
+			snapshots: []api.Snapshot{
+				completedSnapshot("aug", time.Date(2019, time.August, 1, 0, 0, 0, 0, time.UTC)),
+				completedSnapshot("jul-end", time.Date(2019, time.July, 31, 23, 59, 0, 0, time.UTC)),
+				completedSnapshot("jul-start", time.Date(2019, time.July, 1, 0, 0, 0, 0, time.UTC)),
+				completedSnapshot("jun", time.Date(2019, time.June, 30, 0, 0, 0, 0, time.UTC)),
+			},
+			want: map[string]string{
+				"aug":       "keep: keep monthly",
+				"jul-end":   "keep: keep monthly",
+				"jul-start": "delete: not retained",
+				"jun":       "delete: not retained",
+			},
+		},
+		{
+			name:    "rules are combined",
+			options: SnapshotPruneOptions{KeepLast: 1, KeepDaily: 2},
+			snapshots: []api.Snapshot{
+				completedSnapshot("d14-late", day(14, 23)),
+				completedSnapshot("d14-early", day(14, 1)),
+				completedSnapshot("d13", day(13, 1)),
+			},
+			want: map[string]string{
+				"d14-late":  "keep: keep last,daily",
+				"d14-early": "delete: not retained",
+				"d13":       "keep: keep daily",
+			},
+		},
+		{
+			name:    "max age overrides the keep rules",
+			options: SnapshotPruneOptions{KeepLast: 3, KeepMonthly: 3, MaxAge: 48 * time.Hour},
+			snapshots: []api.Snapshot{
+				completedSnapshot("new", day(15, 0)),
+				completedSnapshot("at-limit", day(13, 12)),
+				completedSnapshot("old", day(13, 11)),
+			},
+			want: map[string]string{
+				"new":      "keep: keep last,monthly",
+				"at-limit": "keep: keep last",
+				"old":      "delete: older than max-age",
+			},
+		},
+		{
+			name:    "max age alone keeps the snapshots within the age",
+			options: SnapshotPruneOptions{MaxAge: 48 * time.Hour},
+			snapshots: []api.Snapshot{
+				completedSnapshot("new", day(15, 0)),
+				completedSnapshot("at-limit", day(13, 12)),
+				completedSnapshot("old", day(1, 0)),
+			},
+			want: map[string]string{
+				"new":      "keep: within max-age",
+				"at-limit": "keep: within max-age",
+				"old":      "delete: older than max-age",
+			},
+		},
+		{
+			name:      "incomplete snapshots are never deleted",
+			options:   SnapshotPruneOptions{KeepLast: 1, MaxAge: time.Hour},
+			snapshots: []api.Snapshot{running, failed, noCompletion, completedSnapshot("s1", day(1, 0))},
+			want: map[string]string{
+				"running":       "keep: running",
+				"failed":        "keep: failed",
+				"no-completion": "keep: no completion time",
+				"s1":            "delete: older than max-age",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := planActions(c.options.planRetention("db", c.snapshots, pruneNow))
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("planRetention() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestPlanRetentionIsPerDatabase(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2019, time.August, d, 0, 0, 0, 0, time.UTC)
+	}
+	labeled := func(s api.Snapshot, kind, name string) api.Snapshot {
+		s.Labels = map[string]string{api.LabelDatabaseKind: kind, api.LabelDatabaseName: name}
+		return s
+	}
+	unlabeled := completedSnapshot("legacy", day(1))
+	unlabeled.Spec.DatabaseName = "pg"
+
+	snapshots := []api.Snapshot{
+		labeled(completedSnapshot("pg-2", day(12)), api.ResourceKindPostgres, "pg"),
+		labeled(completedSnapshot("pg-1", day(11)), api.ResourceKindPostgres, "pg"),
+		labeled(completedSnapshot("mg-1", day(10)), api.ResourceKindMongoDB, "mg"),
+		// a database of another kind with the same name is a separate database
+		labeled(completedSnapshot("my-pg-1", day(9)), api.ResourceKindMySQL, "pg"),
+		unlabeled,
+	}
+
+	groups := groupSnapshots(snapshots)
+	var databases []string
+	for db := range groups {
+		databases = append(databases, db.String())
+	}
+	sort.Strings(databases)
+	wantDatabases := []string{"MongoDB/mg", "MySQL/pg", "Postgres/pg", "pg"}
+	if !reflect.DeepEqual(databases, wantDatabases) {
+		t.Fatalf("groupSnapshots() databases = %v, want %v", databases, wantDatabases)
+	}
+
+	o := SnapshotPruneOptions{KeepLast: 1}
+	got := map[string]string{}
+	for db, list := range groups {
+		for name, action := range planActions(o.planRetention(db.String(), list, pruneNow)) {
+			got[name] = action
+		}
+	}
+	want := map[string]string{
+		"pg-2":    "keep: keep last",
+		"pg-1":    "delete: not retained",
+		"mg-1":    "keep: keep last",
+		"my-pg-1": "keep: keep last",
+		"legacy":  "keep: keep last",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planRetention() per database = %v, want %v", got, want)
+	}
+}
+
+func TestPlanFailedSnapshots(t *testing.T) {
+	snapshots := []api.Snapshot{
+		completedSnapshot("ok", pruneNow),
+		{ObjectMeta: metav1.ObjectMeta{Name: "failed"}, Status: api.SnapshotStatus{Phase: api.SnapshotPhaseFailed}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "running"}, Status: api.SnapshotStatus{Phase: api.SnapshotPhaseRunning}},
+	}
+	got := planActions(planFailedSnapshots("db", snapshots))
+	want := map[string]string{"failed": "delete: failed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planFailedSnapshots() = %v, want %v", got, want)
+	}
+}
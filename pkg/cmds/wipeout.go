@@ -0,0 +1,266 @@
+package cmds
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/printers"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
+)
+
+var (
+	wipeoutLong = templates.LongDesc(`
+		Wipe out dormant databases.
+
+		The volume claims, secrets and snapshots of the selected dormant databases are deleted by
+		the KubeDB operator. They are listed before anything is done, and the command asks for
+		confirmation unless --yes is given. Wiped out databases can not be resumed.`)
+
+	wipeoutExample = templates.Examples(`
+		# Wipe out a dormant database
+		kubedb wipeout postgres-demo
+
+		# Wipe out the dormant databases with label app=demo without asking for confirmation
+		kubedb wipeout -l app=demo --yes
+
+		# Wipe out the dormant databases paused more than 30 days ago
+		kubedb wipeout --older-than=720h`)
+)
+
+type WipeOutOptions struct {
+	Namespace string
+	Names     []string
+	Selector  string
+	OlderThan time.Duration
+
+	Yes     bool
+	Timeout time.Duration
+
+	Client       kubernetes.Interface
+	KubedbClient cs.KubedbV1alpha1Interface
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdWipeOut(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &WipeOutOptions{
+		Timeout:   5 * time.Minute,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "wipeout ([NAME...] | -l label | --older-than DURATION)",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Delete the data of dormant databases"),
+		Long:                  wipeoutLong,
+		Example:               wipeoutExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringVarP(&o.Selector, "selector", "l", o.Selector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().DurationVar(&o.OlderThan, "older-than", o.OlderThan, "Only wipe out dormant databases paused longer ago than this, i.e. 720h.")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", o.Yes, "If true, do not ask for confirmation.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait for each dormant database to be wiped out, zero means wait forever.")
+	return cmd
+}
+
+func (o *WipeOutOptions) Complete(f cmdutil.Factory, args []string) error {
+	if len(args) == 0 && o.Selector == "" && o.OlderThan == 0 {
+		return fmt.Errorf("You must specify the dormant databases to wipe out by name, --selector or --older-than.")
+	}
+	for _, arg := range args {
+		name, err := dormantDatabaseName([]string{arg})
+		if err != nil {
+			return err
+		}
+		o.Names = append(o.Names, name)
+	}
+
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	o.Client, err = f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.KubedbClient, err = newKubedbClient(f)
+	return err
+}
+
+func (o *WipeOutOptions) Run() error {
+	dormants, err := o.selectDormantDatabases()
+	if err != nil {
+		return err
+	}
+	if len(dormants) == 0 {
+		fmt.Fprintf(o.Out, "No resources found\n")
+		return nil
+	}
+
+	if err = o.printWipeOutPlan(dormants); err != nil {
+		return err
+	}
+	if !o.Yes && !confirm(o.IOStreams, fmt.Sprintf("Wipe out %d dormant database(s)? This can not be undone.", len(dormants))) {
+		fmt.Fprintf(o.Out, "Aborted\n")
+		return nil
+	}
+
+	var errs []error
+	for _, drmn := range dormants {
+		if err := o.wipeOut(drmn); err != nil {
+			fmt.Fprintf(o.Out, "dormantdatabase %q failed: %v\n", drmn.Name, err)
+			errs = append(errs, fmt.Errorf("dormantdatabase %q: %v", drmn.Name, err))
+			continue
+		}
+		fmt.Fprintf(o.Out, "dormantdatabase %q wiped out\n", drmn.Name)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// selectDormantDatabases returns the dormant databases matching the given names, selector and age,
+// sorted by name. Databases that are already wiped out are skipped.
+func (o *WipeOutOptions) selectDormantDatabases() ([]api.DormantDatabase, error) {
+	list, err := o.KubedbClient.DormantDatabases(o.Namespace).List(metav1.ListOptions{LabelSelector: o.Selector})
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	now := time.Now()
+	var dormants []api.DormantDatabase
+	for _, drmn := range list.Items {
+		found[drmn.Name] = true
+		if len(o.Names) > 0 && !containsString(o.Names, drmn.Name) {
+			continue
+		}
+		if o.OlderThan > 0 && now.Sub(pausingTime(drmn).Time) < o.OlderThan {
+			continue
+		}
+		if drmn.Status.Phase == api.DormantDatabasePhaseWipedOut {
+			fmt.Fprintf(o.ErrOut, "dormantdatabase %q is already wiped out, skipping\n", drmn.Name)
+			continue
+		}
+		dormants = append(dormants, drmn)
+	}
+	for _, name := range o.Names {
+		if !found[name] {
+			return nil, fmt.Errorf("dormantdatabase %q not found", name)
+		}
+	}
+	sort.Slice(dormants, func(i, j int) bool { return dormants[i].Name < dormants[j].Name })
+	return dormants, nil
+}
+
+// pausingTime returns the time the dormant database was paused at, or its creation time if the
+// operator has not recorded the pausing time.
+func pausingTime(drmn api.DormantDatabase) metav1.Time {
+	if drmn.Status.PausingTime != nil {
+		return *drmn.Status.PausingTime
+	}
+	return drmn.CreationTimestamp
+}
+
+// printWipeOutPlan lists the volume claims, secrets and snapshots that will be deleted.
+func (o *WipeOutOptions) printWipeOutPlan(dormants []api.DormantDatabase) error {
+	w := printers.GetNewTabWriter(o.Out)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "The following objects will be deleted:\n")
+	fmt.Fprintf(w, "DORMANTDATABASE\tKIND\tNAME\tSIZE\n")
+	for _, drmn := range dormants {
+		opts := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(drmn.OffshootSelectors()).String()}
+
+		claims, err := o.Client.CoreV1().PersistentVolumeClaims(drmn.Namespace).List(opts)
+		if err != nil {
+			return err
+		}
+		for _, pvc := range claims.Items {
+			fmt.Fprintf(w, "%s\tPersistentVolumeClaim\t%s\t%s\n", drmn.Name, pvc.Name, claimSize(pvc))
+		}
+		for _, secret := range drmn.GetDatabaseSecrets() {
+			if secret == "" {
+				continue
+			}
+			fmt.Fprintf(w, "%s\tSecret\t%s\t\n", drmn.Name, secret)
+		}
+		snapshots, err := o.KubedbClient.Snapshots(drmn.Namespace).List(opts)
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range snapshots.Items {
+			fmt.Fprintf(w, "%s\tSnapshot\t%s\t\n", drmn.Name, snapshot.Name)
+		}
+	}
+	return nil
+}
+
+// confirm asks the question and reports whether it was answered with yes.
+func confirm(streams genericclioptions.IOStreams, question string) bool {
+	fmt.Fprintf(streams.Out, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(streams.In).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// wipeOut sets spec.wipeOut of the dormant database and waits for the operator to delete its data.
+func (o *WipeOutOptions) wipeOut(drmn api.DormantDatabase) error {
+	_, err := o.KubedbClient.DormantDatabases(drmn.Namespace).Patch(drmn.Name, types.MergePatchType, []byte(`{"spec":{"wipeOut":true}}`))
+	if err != nil {
+		return err
+	}
+
+	err = poll(o.Timeout, func() (bool, error) {
+		d, err := o.KubedbClient.DormantDatabases(drmn.Namespace).Get(drmn.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return d.Status.Phase == api.DormantDatabasePhaseWipedOut, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting to be wiped out")
+	}
+	return err
+}
+
+// claimSize returns the capacity of a bound claim, or the requested size otherwise.
+func claimSize(pvc core.PersistentVolumeClaim) string {
+	if size, ok := pvc.Status.Capacity[core.ResourceStorage]; ok {
+		return size.String()
+	}
+	if size, ok := pvc.Spec.Resources.Requests[core.ResourceStorage]; ok {
+		return size.String()
+	}
+	return "<unknown>"
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
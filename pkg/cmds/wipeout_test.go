@@ -0,0 +1,167 @@
+package cmds
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/internal/fakeapi"
+)
+
+// testClients returns the clientsets of the fake API server s.
+func testClients(t *testing.T, s *fakeapi.Server) (kubernetes.Interface, cs.KubedbV1alpha1Interface) {
+	client, err := kubernetes.NewForConfig(s.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubedbClient, err := cs.NewForConfig(s.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, kubedbClient
+}
+
+func dormantDatabase(name string, created time.Time, paused *time.Time, phase api.DormantDatabasePhase) *api.DormantDatabase {
+	drmn := &api.DormantDatabase{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "demo",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{"app": name},
+		},
+		Spec: api.DormantDatabaseSpec{Origin: api.Origin{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "demo"},
+			Spec: api.OriginSpec{Postgres: &api.PostgresSpec{
+				DatabaseSecret: &core.SecretVolumeSource{SecretName: name + "-auth"},
+			}},
+		}},
+		Status: api.DormantDatabaseStatus{Phase: phase},
+	}
+	if paused != nil {
+		t := metav1.NewTime(*paused)
+		drmn.Status.PausingTime = &t
+	}
+	return drmn
+}
+
+func TestSelectDormantDatabases(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(days int) *time.Time {
+		t := now.Add(-time.Duration(days) * 24 * time.Hour)
+		return &t
+	}
+
+	s := fakeapi.NewServer(t)
+	s.Add("/apis/kubedb.com/v1alpha1", api.ResourcePluralDormantDatabase,
+		// created long ago, but only paused recently
+		dormantDatabase("recent", *daysAgo(100), daysAgo(1), api.DormantDatabasePhasePaused),
+		dormantDatabase("old", *daysAgo(100), daysAgo(40), api.DormantDatabasePhasePaused),
+		// the pausing time is not recorded, the creation time is used instead
+		dormantDatabase("unrecorded", *daysAgo(50), nil, api.DormantDatabasePhasePaused),
+		dormantDatabase("wiped", *daysAgo(100), daysAgo(40), api.DormantDatabasePhaseWipedOut),
+		dormantDatabase("another", *daysAgo(100), daysAgo(40), api.DormantDatabasePhasePaused),
+	)
+
+	cases := []struct {
+		name      string
+		names     []string
+		selector  string
+		olderThan time.Duration
+		want      []string
+		wantErr   string
+	}{
+		{name: "by names", names: []string{"recent", "old"}, want: []string{"old", "recent"}},
+		{name: "by selector", selector: "app=old", want: []string{"old"}},
+		{name: "paused longer ago than", olderThan: 30 * 24 * time.Hour, want: []string{"another", "old", "unrecorded"}},
+		{name: "names and age", names: []string{"recent", "old"}, olderThan: 30 * 24 * time.Hour, want: []string{"old"}},
+		{name: "wiped out databases are skipped", names: []string{"wiped"}},
+		{name: "missing name", names: []string{"old", "missing"}, wantErr: `dormantdatabase "missing" not found`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, kubedbClient := testClients(t, s)
+			o := &WipeOutOptions{
+				Namespace:    "demo",
+				Names:        c.names,
+				Selector:     c.selector,
+				OlderThan:    c.olderThan,
+				KubedbClient: kubedbClient,
+				IOStreams:    genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			dormants, err := o.selectDormantDatabases()
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("selectDormantDatabases() error = %v, want %s", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectDormantDatabases() error = %v", err)
+			}
+			var got []string
+			for _, drmn := range dormants {
+				got = append(got, drmn.Name)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("selectDormantDatabases() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestPrintWipeOutPlan(t *testing.T) {
+	drmn := dormantDatabase("pg", time.Now(), nil, api.DormantDatabasePhasePaused)
+	selector := drmn.OffshootSelectors()
+
+	s := fakeapi.NewServer(t)
+	s.Add("/api/v1", "persistentvolumeclaims",
+		&core.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-pg-0", Namespace: "demo", Labels: selector},
+			Status:     core.PersistentVolumeClaimStatus{Capacity: core.ResourceList{core.ResourceStorage: resource.MustParse("2Gi")}},
+		},
+		&core.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-pg-1", Namespace: "demo", Labels: selector},
+			Spec: core.PersistentVolumeClaimSpec{Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{core.ResourceStorage: resource.MustParse("1Gi")},
+			}},
+		},
+		&core.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-other-0", Namespace: "demo"},
+		},
+	)
+	s.Add("/apis/kubedb.com/v1alpha1", api.ResourcePluralSnapshot, &api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg-snap", Namespace: "demo", Labels: selector},
+	})
+
+	client, kubedbClient := testClients(t, s)
+	out := &bytes.Buffer{}
+	o := &WipeOutOptions{
+		Client:       client,
+		KubedbClient: kubedbClient,
+		IOStreams:    genericclioptions.IOStreams{Out: out},
+	}
+	if err := o.printWipeOutPlan([]api.DormantDatabase{*drmn}); err != nil {
+		t.Fatalf("printWipeOutPlan() error = %v", err)
+	}
+
+	want := []string{
+		"The following objects will be deleted:",
+		"DORMANTDATABASE   KIND                    NAME        SIZE",
+		"pg                PersistentVolumeClaim   data-pg-0   2Gi",
+		"pg                PersistentVolumeClaim   data-pg-1   1Gi",
+		"pg                Secret                  pg-auth     ",
+		"pg                Snapshot                pg-snap     ",
+	}
+	if got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("printWipeOutPlan() printed\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
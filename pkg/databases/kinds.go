@@ -0,0 +1,80 @@
+package databases
+
+import (
+	"sort"
+
+	catalog "kubedb.dev/apimachinery/apis/catalog/v1alpha1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+// Kind holds what the cli needs to know about a KubeDB database kind.
+type Kind struct {
+	// Resource is the resource plural of the kind, i.e. postgreses for Postgres.
+	Resource string
+	// CatalogResource is the catalog resource holding the versions of the kind, empty if there is none.
+	CatalogResource string
+	// Snapshots is set if the kind can be backed up with Snapshots.
+	Snapshots bool
+}
+
+// Kinds maps the KubeDB database kinds to their properties. A new database kind is added here.
+var Kinds = map[string]Kind{
+	api.ResourceKindElasticsearch: {
+		Resource:        api.ResourcePluralElasticsearch,
+		CatalogResource: catalog.ResourcePluralElasticsearchVersion,
+		Snapshots:       true,
+	},
+	api.ResourceKindEtcd: {
+		Resource:        api.ResourcePluralEtcd,
+		CatalogResource: catalog.ResourcePluralEtcdVersion,
+		Snapshots:       true,
+	},
+	api.ResourceKindMariaDB: {
+		Resource: api.ResourcePluralMariaDB,
+	},
+	api.ResourceKindMemcached: {
+		Resource:        api.ResourcePluralMemcached,
+		CatalogResource: catalog.ResourcePluralMemcachedVersion,
+	},
+	api.ResourceKindMongoDB: {
+		Resource:        api.ResourcePluralMongoDB,
+		CatalogResource: catalog.ResourcePluralMongoDBVersion,
+		Snapshots:       true,
+	},
+	api.ResourceKindMySQL: {
+		Resource:        api.ResourcePluralMySQL,
+		CatalogResource: catalog.ResourcePluralMySQLVersion,
+		Snapshots:       true,
+	},
+	api.ResourceKindPerconaXtraDB: {
+		Resource:        api.ResourcePluralPerconaXtraDB,
+		CatalogResource: catalog.ResourcePluralPerconaXtraDBVersion,
+	},
+	api.ResourceKindPostgres: {
+		Resource:        api.ResourcePluralPostgres,
+		CatalogResource: catalog.ResourcePluralPostgresVersion,
+		Snapshots:       true,
+	},
+	api.ResourceKindRedis: {
+		Resource:        api.ResourcePluralRedis,
+		CatalogResource: catalog.ResourcePluralRedisVersion,
+	},
+}
+
+// IsDatabase reports whether kind is a KubeDB database kind.
+func IsDatabase(kind string) bool {
+	_, ok := Kinds[kind]
+	return ok
+}
+
+// SnapshotKinds returns the sorted database kinds that can be backed up with Snapshots.
+func SnapshotKinds() []string {
+	var kinds []string
+	for kind, k := range Kinds {
+		if k.Snapshots {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
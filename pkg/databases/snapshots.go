@@ -0,0 +1,31 @@
+package databases
+
+import (
+	batch "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+// SnapshotJobSelector returns the selector of the jobs the operator may have run to take the
+// snapshot s, which carry the database kind label of s. It returns nil if s has no such label.
+func SnapshotJobSelector(s *api.Snapshot) labels.Selector {
+	kind := s.Labels[api.LabelDatabaseKind]
+	if kind == "" {
+		return nil
+	}
+	return labels.SelectorFromSet(map[string]string{api.LabelDatabaseKind: kind})
+}
+
+// IsSnapshotJob reports whether job is the backup job of the snapshot s, that is, the job is owned
+// by s or, failing an owner reference, is a backup job named after s.
+func IsSnapshotJob(job *batch.Job, s *api.Snapshot) bool {
+	for _, ref := range job.OwnerReferences {
+		if ref.UID == s.UID {
+			return true
+		}
+	}
+	if job.Annotations[api.AnnotationJobType] == api.JobTypeRestore {
+		return false
+	}
+	return job.Name == s.OffshootName() || job.Name == api.DatabaseNamePrefix+"-"+s.OffshootName()
+}
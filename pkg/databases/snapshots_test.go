@@ -0,0 +1,46 @@
+package databases
+
+import (
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+func TestIsSnapshotJob(t *testing.T) {
+	snapshot := &api.Snapshot{ObjectMeta: metav1.ObjectMeta{Name: "snap", UID: "snap-uid"}}
+	owned := []metav1.OwnerReference{{Kind: api.ResourceKindSnapshot, Name: "snap", UID: "snap-uid"}}
+	ownedByOther := []metav1.OwnerReference{{Kind: api.ResourceKindSnapshot, Name: "other", UID: "other-uid"}}
+	restore := map[string]string{api.AnnotationJobType: api.JobTypeRestore}
+
+	cases := []struct {
+		name string
+		job  metav1.ObjectMeta
+		want bool
+	}{
+		{"owned", metav1.ObjectMeta{Name: "anything", OwnerReferences: owned}, true},
+		{"named after the snapshot", metav1.ObjectMeta{Name: "snap"}, true},
+		{"named after the snapshot with the kubedb prefix", metav1.ObjectMeta{Name: "kubedb-snap"}, true},
+		{"restore job named after the snapshot", metav1.ObjectMeta{Name: "kubedb-snap", Annotations: restore}, false},
+		{"named after another snapshot", metav1.ObjectMeta{Name: "kubedb-other"}, false},
+		{"owned by another snapshot", metav1.ObjectMeta{Name: "anything", OwnerReferences: ownedByOther}, false},
+	}
+	for _, c := range cases {
+		if got := IsSnapshotJob(&batch.Job{ObjectMeta: c.job}, snapshot); got != c.want {
+			t.Errorf("%s: IsSnapshotJob() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestSnapshotJobSelector(t *testing.T) {
+	if s := SnapshotJobSelector(&api.Snapshot{}); s != nil {
+		t.Errorf("SnapshotJobSelector() of an unlabeled snapshot = %v, want nil", s)
+	}
+	snapshot := &api.Snapshot{ObjectMeta: metav1.ObjectMeta{
+		Labels: map[string]string{api.LabelDatabaseKind: api.ResourceKindPostgres, api.LabelDatabaseName: "pg"},
+	}}
+	if s := SnapshotJobSelector(snapshot); s == nil || s.String() != "kubedb.com/kind=Postgres" {
+		t.Errorf("SnapshotJobSelector() = %v, want kubedb.com/kind=Postgres", s)
+	}
+}
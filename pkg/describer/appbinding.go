@@ -0,0 +1,89 @@
+package describer
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
+)
+
+// AppBindingDescription holds the connection information published in the AppBinding of a database.
+type AppBindingDescription struct {
+	Name         string                `json:"name"`
+	Type         appcat.AppType        `json:"type,omitempty"`
+	Version      string                `json:"version,omitempty"`
+	URL          string                `json:"url,omitempty"`
+	ClientConfig appcat.ClientConfig   `json:"clientConfig"`
+	Secret       string                `json:"secret,omitempty"`
+	Parameters   *runtime.RawExtension `json:"parameters,omitempty"`
+}
+
+// GetAppBinding fetches the AppBinding with the given name and summarizes its connection information.
+func GetAppBinding(dc dynamic.Interface, namespace, name string) (*AppBindingDescription, error) {
+	obj, err := dc.Resource(appcat.SchemeGroupVersion.WithResource(appcat.ResourceApps)).Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var app appcat.AppBinding
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &app); err != nil {
+		return nil, err
+	}
+
+	desc := &AppBindingDescription{
+		Name:         app.Name,
+		Type:         app.Spec.Type,
+		Version:      app.Spec.Version,
+		ClientConfig: app.Spec.ClientConfig,
+		Parameters:   app.Spec.Parameters,
+	}
+	if u, err := app.URL(); err == nil {
+		desc.URL = u
+	}
+	if app.Spec.Secret != nil {
+		desc.Secret = app.Spec.Secret.Name
+	}
+	return desc, nil
+}
+
+func describeAppBinding(app *AppBindingDescription, w versioned.PrefixWriter) {
+	if app == nil {
+		return
+	}
+
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "AppBinding:\n")
+	w.Write(LEVEL_1, "Name:\t%s\n", app.Name)
+	w.Write(LEVEL_1, "Type:\t%s\n", app.Type)
+	if app.Version != "" {
+		w.Write(LEVEL_1, "Version:\t%s\n", app.Version)
+	}
+	w.Write(LEVEL_1, "ClientConfig:\n")
+	if app.URL != "" {
+		w.Write(LEVEL_2, "URL:\t%s\n", app.URL)
+	}
+	if svc := app.ClientConfig.Service; svc != nil {
+		w.Write(LEVEL_2, "Service:\n")
+		w.Write(LEVEL_3, "Name:\t%s\n", svc.Name)
+		w.Write(LEVEL_3, "Port:\t%d\n", svc.Port)
+		w.Write(LEVEL_3, "Scheme:\t%s\n", svc.Scheme)
+		if svc.Path != "" {
+			w.Write(LEVEL_3, "Path:\t%s\n", svc.Path)
+		}
+		if svc.Query != "" {
+			w.Write(LEVEL_3, "Query:\t%s\n", svc.Query)
+		}
+	}
+	if app.ClientConfig.InsecureSkipTLSVerify {
+		w.Write(LEVEL_2, "InsecureSkipTLSVerify:\ttrue\n")
+	}
+	if len(app.ClientConfig.CABundle) > 0 {
+		w.Write(LEVEL_2, "CABundle:\t%d bytes\n", len(app.ClientConfig.CABundle))
+	}
+	if app.Secret != "" {
+		w.Write(LEVEL_1, "Secret:\t%s\n", app.Secret)
+	}
+	if app.Parameters != nil && len(app.Parameters.Raw) > 0 {
+		w.Write(LEVEL_1, "Parameters:\t%s\n", string(app.Parameters.Raw))
+	}
+}
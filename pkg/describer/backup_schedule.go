@@ -0,0 +1,112 @@
+package describer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	store "kmodules.xyz/objectstore-api/api/v1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+// scheduledRuns is the number of upcoming backup runs shown by describe.
+const scheduledRuns = 3
+
+// BackupScheduleDescription summarizes the backup schedule of a database and how it is being kept.
+type BackupScheduleDescription struct {
+	CronExpression         string        `json:"cronExpression"`
+	Location               string        `json:"location,omitempty"`
+	Backend                store.Backend `json:"backend"`
+	NextRuns               []metav1.Time `json:"nextRuns,omitempty"`
+	LastSuccessfulSnapshot string        `json:"lastSuccessfulSnapshot,omitempty"`
+	LastSuccessTime        *metav1.Time  `json:"lastSuccessTime,omitempty"`
+	Warning                string        `json:"warning,omitempty"`
+}
+
+// summarizeBackupSchedule computes the upcoming runs of schedule and checks the most recent
+// successful snapshot against it. A snapshot older than two schedule intervals is reported as a warning.
+func summarizeBackupSchedule(schedule *api.BackupScheduleSpec, snapshots []api.Snapshot, now time.Time) *BackupScheduleDescription {
+	if schedule == nil {
+		return nil
+	}
+
+	desc := &BackupScheduleDescription{
+		CronExpression: schedule.CronExpression,
+		Backend:        schedule.Backend,
+	}
+	if location, err := schedule.Backend.Location(); err == nil {
+		desc.Location = location
+	} else {
+		desc.Location = "<invalid>"
+	}
+
+	for i := range snapshots {
+		s := &snapshots[i]
+		if s.Status.Phase != api.SnapshotPhaseSucceeded || s.Status.CompletionTime == nil {
+			continue
+		}
+		if desc.LastSuccessTime == nil || desc.LastSuccessTime.Before(s.Status.CompletionTime) {
+			desc.LastSuccessfulSnapshot = s.Name
+			desc.LastSuccessTime = s.Status.CompletionTime
+		}
+	}
+
+	sched, err := parseCronExpression(schedule.CronExpression)
+	if err != nil {
+		desc.Warning = fmt.Sprintf("invalid cron expression %q: %v", schedule.CronExpression, err)
+		return desc
+	}
+	next := now
+	for i := 0; i < scheduledRuns; i++ {
+		next = sched.Next(next)
+		if next.IsZero() {
+			break
+		}
+		desc.NextRuns = append(desc.NextRuns, metav1.NewTime(next))
+	}
+
+	if len(desc.NextRuns) > 1 && desc.LastSuccessTime != nil {
+		interval := desc.NextRuns[1].Sub(desc.NextRuns[0].Time)
+		if age := now.Sub(desc.LastSuccessTime.Time); age > 2*interval {
+			desc.Warning = fmt.Sprintf("last successful snapshot is %s old, more than two schedule intervals (%s)",
+				duration.HumanDuration(age), duration.HumanDuration(interval))
+		}
+	}
+	return desc
+}
+
+// parseCronExpression parses expr with the parser the operator schedules backups with, cron.Parse:
+// six fields with a leading seconds field and an optional day of week, or a descriptor like @every 6h.
+// A standard five field expression is hence read as starting with seconds, as the operator does.
+func parseCronExpression(expr string) (cron.Schedule, error) {
+	return cron.Parse(expr)
+}
+
+func describeBackupSchedule(desc *BackupScheduleDescription, w versioned.PrefixWriter) {
+	if desc == nil {
+		return
+	}
+
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "Backup Schedule:\n")
+	w.Write(LEVEL_1, "CronExpression:\t%s\n", desc.CronExpression)
+	if len(desc.NextRuns) > 0 {
+		w.Write(LEVEL_1, "Next Runs:\n")
+		for i := range desc.NextRuns {
+			w.Write(LEVEL_2, "%s\n", timeToString(&desc.NextRuns[i]))
+		}
+	}
+	if desc.LastSuccessTime != nil {
+		w.Write(LEVEL_1, "Last Successful Snapshot:\t%s (%s ago)\n", desc.LastSuccessfulSnapshot, translateTimestamp(*desc.LastSuccessTime))
+	} else {
+		w.Write(LEVEL_1, "Last Successful Snapshot:\t<none>\n")
+	}
+	if desc.Warning != "" {
+		w.Write(LEVEL_1, "WARNING:\t%s\n", desc.Warning)
+	}
+	w.Write(LEVEL_1, "Storage:\n")
+	describeSnapshotStorage(desc.Backend, w)
+}
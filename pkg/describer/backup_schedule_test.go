@@ -0,0 +1,127 @@
+package describer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	store "kmodules.xyz/objectstore-api/api/v1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+func TestParseCronExpression(t *testing.T) {
+	now := time.Date(2019, time.August, 15, 12, 0, 0, 0, time.UTC)
+	at := func(day, hour, min, sec int) time.Time {
+		return time.Date(2019, time.August, day, hour, min, sec, 0, time.UTC)
+	}
+
+	cases := []struct {
+		expr    string
+		want    []time.Time
+		wantErr bool
+	}{
+		// seconds first, like the operator
+		{expr: "0 30 2 * * *", want: []time.Time{at(16, 2, 30, 0), at(17, 2, 30, 0)}},
+		// five fields are read as seconds, minutes, hours, day of month and month, not as a standard cron expression
+		{expr: "0 30 * * *", want: []time.Time{at(15, 12, 30, 0), at(15, 13, 30, 0)}},
+		{expr: "@every 6h", want: []time.Time{at(15, 18, 0, 0), at(16, 0, 0, 0)}},
+		{expr: "@daily", want: []time.Time{at(16, 0, 0, 0), at(17, 0, 0, 0)}},
+		{expr: "0 2 * *", wantErr: true},
+		{expr: "every day", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			sched, err := parseCronExpression(c.expr)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("parseCronExpression() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCronExpression() error = %v", err)
+			}
+			var got []time.Time
+			for next := now; len(got) < len(c.want); {
+				next = sched.Next(next)
+				got = append(got, next)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("parseCronExpression() runs = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeBackupSchedule(t *testing.T) {
+	now := time.Date(2019, time.August, 15, 12, 0, 0, 0, time.UTC)
+	schedule := &api.BackupScheduleSpec{
+		// every six hours
+		CronExpression: "0 0 */6 * * *",
+		Backend:        store.Backend{GCS: &store.GCSSpec{Bucket: "backups", Prefix: "pg"}},
+	}
+	snapshot := func(name string, phase api.SnapshotPhase, age time.Duration) api.Snapshot {
+		completed := metav1.NewTime(now.Add(-age))
+		return api.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     api.SnapshotStatus{Phase: phase, CompletionTime: &completed},
+		}
+	}
+
+	cases := []struct {
+		name        string
+		schedule    *api.BackupScheduleSpec
+		snapshots   []api.Snapshot
+		wantLast    string
+		wantWarning string
+	}{
+		{
+			name: "recent snapshot",
+			snapshots: []api.Snapshot{
+				snapshot("old", api.SnapshotPhaseSucceeded, 30*time.Hour),
+				snapshot("recent", api.SnapshotPhaseSucceeded, 5*time.Hour),
+				snapshot("failed", api.SnapshotPhaseFailed, time.Hour),
+			},
+			wantLast: "recent",
+		},
+		{
+			name:      "snapshot exactly two intervals old",
+			snapshots: []api.Snapshot{snapshot("at-limit", api.SnapshotPhaseSucceeded, 12*time.Hour)},
+			wantLast:  "at-limit",
+		},
+		{
+			name:        "snapshot older than two intervals",
+			snapshots:   []api.Snapshot{snapshot("stale", api.SnapshotPhaseSucceeded, 12*time.Hour+time.Minute)},
+			wantLast:    "stale",
+			wantWarning: "last successful snapshot is 12h old, more than two schedule intervals (6h)",
+		},
+		{
+			name:      "no successful snapshot",
+			snapshots: []api.Snapshot{snapshot("failed", api.SnapshotPhaseFailed, 30*time.Hour)},
+		},
+		{
+			name:        "invalid cron expression",
+			schedule:    &api.BackupScheduleSpec{CronExpression: "0 2 * *"},
+			wantWarning: `invalid cron expression "0 2 * *": Expected 5 to 6 fields, found 4: 0 2 * *`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := schedule
+			if c.schedule != nil {
+				s = c.schedule
+			}
+			desc := summarizeBackupSchedule(s, c.snapshots, now)
+			if desc.LastSuccessfulSnapshot != c.wantLast {
+				t.Errorf("summarizeBackupSchedule() last successful snapshot = %q, want %q", desc.LastSuccessfulSnapshot, c.wantLast)
+			}
+			if desc.Warning != c.wantWarning {
+				t.Errorf("summarizeBackupSchedule() warning = %q, want %q", desc.Warning, c.wantWarning)
+			}
+			if c.schedule == nil && len(desc.NextRuns) != scheduledRuns {
+				t.Errorf("summarizeBackupSchedule() next runs = %v, want %d runs", desc.NextRuns, scheduledRuns)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package describer
+
+import (
+	"sync"
+
+	apps "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
+)
+
+// objectCache serves the objects looked up while describing. Lists are requested with the label
+// selector of the database, single objects by name, and each distinct request is sent once, so the
+// objects shared by several describers or sections, i.e. the pods of a database, are fetched once.
+// Failed requests are never cached, the next lookup retries them. An objectCache is meant to live
+// as long as a single command invocation.
+//
+// The returned objects are shared between lookups and must not be modified.
+type objectCache struct {
+	client kubernetes.Interface
+	kubedb cs.KubedbV1alpha1Interface
+
+	mu      sync.Mutex
+	entries map[cacheKey]*cacheEntry
+}
+
+// cacheKey identifies a request: a list by its selectors, or a single object by its name.
+type cacheKey struct {
+	resource  string
+	namespace string
+	selector  string
+	name      string
+}
+
+type cacheEntry struct {
+	mu  sync.Mutex
+	obj runtime.Object
+}
+
+func newObjectCache(client kubernetes.Interface, kubedb cs.KubedbV1alpha1Interface) *objectCache {
+	return &objectCache{
+		client:  client,
+		kubedb:  kubedb,
+		entries: map[cacheKey]*cacheEntry{},
+	}
+}
+
+// fetch returns the result of the request identified by key, calling fetch unless it succeeded before.
+// Concurrent lookups of the same key wait for the first one to finish.
+func (c *objectCache) fetch(key cacheKey, fetch func() (runtime.Object, error)) (runtime.Object, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &cacheEntry{}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.obj == nil {
+		obj, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		entry.obj = obj
+	}
+	return entry.obj, nil
+}
+
+// list returns the objects of resource in namespace matched by opts.
+func (c *objectCache) list(resource, namespace string, opts metav1.ListOptions, list func(opts metav1.ListOptions) (runtime.Object, error)) (runtime.Object, error) {
+	key := cacheKey{resource: resource, namespace: namespace, selector: opts.LabelSelector + ";" + opts.FieldSelector}
+	return c.fetch(key, func() (runtime.Object, error) {
+		return list(opts)
+	})
+}
+
+// get returns the object of resource in namespace named name.
+func (c *objectCache) get(resource, namespace, name string, get func() (runtime.Object, error)) (runtime.Object, error) {
+	return c.fetch(cacheKey{resource: resource, namespace: namespace, name: name}, get)
+}
+
+func selectorOptions(selector labels.Selector) metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: selector.String()}
+}
+
+func (c *objectCache) statefulSets(namespace string, selector labels.Selector) ([]apps.StatefulSet, error) {
+	list, err := c.list("statefulsets", namespace, selectorOptions(selector), func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.client.AppsV1().StatefulSets(namespace).List(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]apps.StatefulSet(nil), list.(*apps.StatefulSetList).Items...), nil
+}
+
+func (c *objectCache) statefulSet(namespace, name string) (*apps.StatefulSet, error) {
+	obj, err := c.get("statefulsets", namespace, name, func() (runtime.Object, error) {
+		return c.client.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*apps.StatefulSet), nil
+}
+
+func (c *objectCache) deployments(namespace string, selector labels.Selector) ([]apps.Deployment, error) {
+	list, err := c.list("deployments", namespace, selectorOptions(selector), func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.client.AppsV1().Deployments(namespace).List(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]apps.Deployment(nil), list.(*apps.DeploymentList).Items...), nil
+}
+
+func (c *objectCache) services(namespace string, selector labels.Selector) ([]core.Service, error) {
+	list, err := c.list("services", namespace, selectorOptions(selector), func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.client.CoreV1().Services(namespace).List(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]core.Service(nil), list.(*core.ServiceList).Items...), nil
+}
+
+func (c *objectCache) service(namespace, name string) (*core.Service, error) {
+	obj, err := c.get("services", namespace, name, func() (runtime.Object, error) {
+		return c.client.CoreV1().Services(namespace).Get(name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*core.Service), nil
+}
+
+func (c *objectCache) endpoints(namespace, name string) (*core.Endpoints, error) {
+	obj, err := c.get("endpoints", namespace, name, func() (runtime.Object, error) {
+		return c.client.CoreV1().Endpoints(namespace).Get(name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*core.Endpoints), nil
+}
+
+func (c *objectCache) pods(namespace string, selector labels.Selector) ([]core.Pod, error) {
+	list, err := c.list("pods", namespace, selectorOptions(selector), func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.client.CoreV1().Pods(namespace).List(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]core.Pod(nil), list.(*core.PodList).Items...), nil
+}
+
+func (c *objectCache) persistentVolumeClaims(namespace string, selector labels.Selector) ([]core.PersistentVolumeClaim, error) {
+	list, err := c.list("persistentvolumeclaims", namespace, selectorOptions(selector), func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.client.CoreV1().PersistentVolumeClaims(namespace).List(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]core.PersistentVolumeClaim(nil), list.(*core.PersistentVolumeClaimList).Items...), nil
+}
+
+func (c *objectCache) jobs(namespace string, selector labels.Selector) ([]batch.Job, error) {
+	list, err := c.list("jobs", namespace, selectorOptions(selector), func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.client.BatchV1().Jobs(namespace).List(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]batch.Job(nil), list.(*batch.JobList).Items...), nil
+}
+
+// snapshots returns the snapshots matched by selector, an empty slice if there are none.
+func (c *objectCache) snapshots(namespace string, selector labels.Selector) ([]api.Snapshot, error) {
+	list, err := c.list("snapshots", namespace, selectorOptions(selector), func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.kubedb.Snapshots(namespace).List(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(make([]api.Snapshot, 0), list.(*api.SnapshotList).Items...), nil
+}
+
+// events returns the events involving the object with the given uid.
+func (c *objectCache) events(namespace string, uid types.UID) ([]core.Event, error) {
+	opts := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", string(uid)).String()}
+	list, err := c.list("events", namespace, opts, func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.client.CoreV1().Events(namespace).List(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]core.Event(nil), list.(*core.EventList).Items...), nil
+}
+
+func (c *objectCache) secret(namespace, name string) (*core.Secret, error) {
+	obj, err := c.get("secrets", namespace, name, func() (runtime.Object, error) {
+		return c.client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*core.Secret), nil
+}
+
+func (c *objectCache) configMap(namespace, name string) (*core.ConfigMap, error) {
+	obj, err := c.get("configmaps", namespace, name, func() (runtime.Object, error) {
+		return c.client.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*core.ConfigMap), nil
+}
+
+func (c *objectCache) podDisruptionBudget(namespace, name string) (*policy.PodDisruptionBudget, error) {
+	obj, err := c.get("poddisruptionbudgets", namespace, name, func() (runtime.Object, error) {
+		return c.client.PolicyV1beta1().PodDisruptionBudgets(namespace).Get(name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*policy.PodDisruptionBudget), nil
+}
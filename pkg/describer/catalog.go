@@ -0,0 +1,137 @@
+package describer
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	catalog "kubedb.dev/apimachinery/apis/catalog/v1alpha1"
+	"kubedb.dev/cli/pkg/databases"
+)
+
+// CatalogVersion holds the fields shared by the catalog version kinds
+// (PostgresVersion, MongoDBVersion, ...). Images a kind does not define are left empty.
+type CatalogVersion struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+
+	DB struct {
+		Image string `json:"image"`
+	} `json:"db"`
+	Exporter struct {
+		Image string `json:"image,omitempty"`
+	} `json:"exporter,omitempty"`
+	Tools struct {
+		Image string `json:"image,omitempty"`
+	} `json:"tools,omitempty"`
+	InitContainer struct {
+		Image string `json:"image,omitempty"`
+	} `json:"initContainer,omitempty"`
+	Proxysql struct {
+		Image string `json:"image,omitempty"`
+	} `json:"proxysql,omitempty"`
+	PodSecurityPolicies struct {
+		DatabasePolicyName    string `json:"databasePolicyName,omitempty"`
+		SnapshotterPolicyName string `json:"snapshotterPolicyName,omitempty"`
+	} `json:"podSecurityPolicies,omitempty"`
+}
+
+// HasCatalogVersion reports whether versions of the given database kind are listed in the catalog.
+func HasCatalogVersion(kind string) bool {
+	return databases.Kinds[kind].CatalogResource != ""
+}
+
+// GetCatalogVersion fetches the catalog version object named version for the given database kind.
+func GetCatalogVersion(dc dynamic.Interface, kind, version string) (*CatalogVersion, error) {
+	plural := databases.Kinds[kind].CatalogResource
+	if plural == "" {
+		return nil, fmt.Errorf("no catalog version kind is known for %s", kind)
+	}
+	gvr := schema.GroupVersionResource{
+		Group:    catalog.SchemeGroupVersion.Group,
+		Version:  catalog.SchemeGroupVersion.Version,
+		Resource: plural,
+	}
+	obj, err := dc.Resource(gvr).Get(version, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return catalogVersionFromUnstructured(obj)
+}
+
+func catalogVersionFromUnstructured(obj *unstructured.Unstructured) (*CatalogVersion, error) {
+	spec, ok := obj.Object["spec"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s %s has no spec", obj.GetKind(), obj.GetName())
+	}
+	cv := &CatalogVersion{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, cv); err != nil {
+		return nil, err
+	}
+	cv.Name = obj.GetName()
+	return cv, nil
+}
+
+// getDatabaseVersion resolves the catalog version used by a database. A version that can not
+// be resolved or is deprecated is reported in the VersionWarning of desc.
+func getDatabaseVersion(dc dynamic.Interface, kind, version string, desc *Description) {
+	cv, err := GetCatalogVersion(dc, kind, version)
+	if err != nil {
+		desc.warnUnlessNotFound(fmt.Sprintf("catalog version %s", version), err)
+		desc.VersionWarning = fmt.Sprintf("catalog version %q could not be resolved: %v", version, err)
+		return
+	}
+	desc.CatalogVersion = cv
+	if cv.Deprecated {
+		desc.VersionWarning = fmt.Sprintf("version %q is deprecated, consider upgrading to a supported version", cv.Name)
+	}
+}
+
+func describeDatabaseVersion(desc *Description, w versioned.PrefixWriter) {
+	cv := desc.CatalogVersion
+	switch {
+	case cv == nil:
+		w.Write(LEVEL_0, "Version:\t%s\n", desc.Spec.Version)
+	case cv.Deprecated:
+		w.Write(LEVEL_0, "Version:\t%s (DEPRECATED)\n", cv.Name)
+	default:
+		w.Write(LEVEL_0, "Version:\t%s\n", cv.Name)
+	}
+	if desc.VersionWarning != "" {
+		w.Write(LEVEL_0, "WARNING:\t%s\n", desc.VersionWarning)
+	}
+	if cv != nil {
+		w.Write(LEVEL_1, "Database Version:\t%s\n", cv.Version)
+		printCatalogImages(LEVEL_1, cv, w)
+	}
+}
+
+func printCatalogImages(level int, cv *CatalogVersion, w versioned.PrefixWriter) {
+	w.Write(level, "DB Image:\t%s\n", cv.DB.Image)
+	if cv.Exporter.Image != "" {
+		w.Write(level, "Exporter Image:\t%s\n", cv.Exporter.Image)
+	}
+	if cv.Tools.Image != "" {
+		w.Write(level, "Tools Image:\t%s\n", cv.Tools.Image)
+	}
+	if cv.InitContainer.Image != "" {
+		w.Write(level, "InitContainer Image:\t%s\n", cv.InitContainer.Image)
+	}
+	if cv.Proxysql.Image != "" {
+		w.Write(level, "Proxysql Image:\t%s\n", cv.Proxysql.Image)
+	}
+	if psp := cv.PodSecurityPolicies; psp.DatabasePolicyName != "" || psp.SnapshotterPolicyName != "" {
+		w.Write(level, "PodSecurityPolicies:\n")
+		if psp.DatabasePolicyName != "" {
+			w.Write(level+1, "Database:\t%s\n", psp.DatabasePolicyName)
+		}
+		if psp.SnapshotterPolicyName != "" {
+			w.Write(level+1, "Snapshotter:\t%s\n", psp.SnapshotterPolicyName)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package describer
+
+import (
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kubernetes/pkg/kubectl/describe"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	catalog "kubedb.dev/apimachinery/apis/catalog/v1alpha1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/databases"
+)
+
+// CatalogVersionDescriber describes the catalog version objects of a database kind,
+// i.e. PostgresVersion for Postgres.
+type CatalogVersionDescriber struct {
+	dc   dynamic.Interface
+	kind string
+	describerOptions
+}
+
+// CatalogDatabaseDescription is a database using a catalog version.
+type CatalogDatabaseDescription struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status,omitempty"`
+}
+
+func (d *CatalogVersionDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+	desc, err := d.describe(name)
+	if err != nil {
+		return "", err
+	}
+	return describeWithWarnings(d.strict, desc, func(w versioned.PrefixWriter) {
+		describeCatalogVersionObject(desc, w)
+	})
+}
+
+func (d *CatalogVersionDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	desc, err := d.describe(name)
+	if err != nil {
+		return nil, err
+	}
+	return desc, incompleteDescription(d.strict, desc)
+}
+
+func (d *CatalogVersionDescriber) describe(name string) (*Description, error) {
+	gvr := catalog.SchemeGroupVersion.WithResource(databases.Kinds[d.kind].CatalogResource)
+	obj, err := d.dc.Resource(gvr).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	cv, err := catalogVersionFromUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &Description{
+		Kind:              obj.GetKind(),
+		Name:              obj.GetName(),
+		CreationTimestamp: obj.GetCreationTimestamp(),
+		Labels:            obj.GetLabels(),
+		Annotations:       obj.GetAnnotations(),
+		CatalogVersion:    cv,
+	}
+	if databases, err := d.listDatabases(name); err == nil {
+		desc.Databases = databases
+	} else {
+		desc.warn("Databases", err)
+	}
+	return desc, nil
+}
+
+// listDatabases returns the databases in all namespaces that use the given version.
+func (d *CatalogVersionDescriber) listDatabases(version string) ([]CatalogDatabaseDescription, error) {
+	gvr := api.SchemeGroupVersion.WithResource(databases.Kinds[d.kind].Resource)
+	list, err := d.dc.Resource(gvr).Namespace(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	databases := make([]CatalogDatabaseDescription, 0)
+	for _, db := range list.Items {
+		if v, _, _ := unstructured.NestedString(db.Object, "spec", "version"); v == version {
+			phase, _, _ := unstructured.NestedString(db.Object, "status", "phase")
+			databases = append(databases, CatalogDatabaseDescription{
+				Namespace: db.GetNamespace(),
+				Name:      db.GetName(),
+				Status:    phase,
+			})
+		}
+	}
+	sort.Slice(databases, func(i, j int) bool {
+		if databases[i].Namespace != databases[j].Namespace {
+			return databases[i].Namespace < databases[j].Namespace
+		}
+		return databases[i].Name < databases[j].Name
+	})
+	return databases, nil
+}
+
+func describeCatalogVersionObject(desc *Description, w versioned.PrefixWriter) {
+	cv := desc.CatalogVersion
+	w.Write(LEVEL_0, "Name:\t%s\n", desc.Name)
+	w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&desc.CreationTimestamp))
+	printLabelsMultiline(LEVEL_0, w, "Labels", desc.Labels)
+	printAnnotationsMultiline(LEVEL_0, w, "Annotations", desc.Annotations)
+	w.Write(LEVEL_0, "Version:\t%s\n", cv.Version)
+	w.Write(LEVEL_0, "Deprecated:\t%v\n", cv.Deprecated)
+	printCatalogImages(LEVEL_0, cv, w)
+
+	if desc.Databases == nil {
+		return
+	}
+	w.Write(LEVEL_0, "\n")
+	if len(desc.Databases) == 0 {
+		w.Write(LEVEL_0, "No Databases.\n")
+		return
+	}
+	w.Write(LEVEL_0, "Databases:\n")
+	w.Write(LEVEL_1, "Namespace\tName\tStatus\n")
+	w.Write(LEVEL_1, "---------\t----\t------\n")
+	for _, db := range desc.Databases {
+		w.Write(LEVEL_1, "%s\t%s\t%s\n", db.Namespace, db.Name, orNone(db.Status))
+	}
+}
@@ -0,0 +1,172 @@
+package describer
+
+import (
+	"fmt"
+	"sort"
+
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+)
+
+// ConfigDescriber is implemented by the describers of database kinds that accept a custom
+// configuration through ConfigSource.
+type ConfigDescriber interface {
+	// ShowConfig enables printing the contents of ConfigMap backed configuration files.
+	ShowConfig(show bool)
+}
+
+// describerOptions holds the settings of the KubeDB describers that are not part of
+// describe.DescriberSettings. It is embedded by the describers to implement ConfigDescriber,
+// StrictDescriber and EventsDescriber.
+type describerOptions struct {
+	showConfig bool
+	strict     bool
+	deepEvents bool
+	eventsType string
+}
+
+func (o *describerOptions) ShowConfig(show bool) {
+	o.showConfig = show
+}
+
+func (o *describerOptions) Strict(strict bool) {
+	o.strict = strict
+}
+
+// ConfigSourceDescription holds the configuration files provided to a database through its ConfigSource.
+type ConfigSourceDescription struct {
+	Kind     string                  `json:"kind"`
+	Name     string                  `json:"name,omitempty"`
+	Optional bool                    `json:"optional,omitempty"`
+	Files    []ConfigFileDescription `json:"files,omitempty"`
+	Warning  string                  `json:"warning,omitempty"`
+	// Source is the volume source of configurations that are neither a ConfigMap nor a Secret.
+	Source *core.VolumeSource `json:"source,omitempty"`
+
+	// err is the error reading the referenced object, unless it does not exist.
+	err error
+}
+
+type ConfigFileDescription struct {
+	Name    string `json:"name"`
+	Size    int    `json:"size"`
+	Content string `json:"content,omitempty"`
+}
+
+// getConfigSource resolves the ConfigMap or Secret referenced by source. Contents are only
+// included for ConfigMap backed files and only if showContent is set; Secret data is never returned.
+func getConfigSource(cache *objectCache, namespace string, source *core.VolumeSource, showContent bool) *ConfigSourceDescription {
+	if source == nil {
+		return nil
+	}
+
+	switch {
+	case source.ConfigMap != nil:
+		desc := &ConfigSourceDescription{
+			Kind:     "ConfigMap",
+			Name:     source.ConfigMap.Name,
+			Optional: source.ConfigMap.Optional != nil && *source.ConfigMap.Optional,
+		}
+		cm, err := cache.configMap(namespace, source.ConfigMap.Name)
+		if err != nil {
+			desc.Warning = configSourceWarning(desc, err)
+			return desc
+		}
+		data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+		for k, v := range cm.Data {
+			data[k] = []byte(v)
+		}
+		for k, v := range cm.BinaryData {
+			data[k] = v
+		}
+		desc.Files = configFiles(data, source.ConfigMap.Items, showContent)
+		return desc
+	case source.Secret != nil:
+		desc := &ConfigSourceDescription{
+			Kind:     "Secret",
+			Name:     source.Secret.SecretName,
+			Optional: source.Secret.Optional != nil && *source.Secret.Optional,
+		}
+		secret, err := cache.secret(namespace, source.Secret.SecretName)
+		if err != nil {
+			desc.Warning = configSourceWarning(desc, err)
+			return desc
+		}
+		desc.Files = configFiles(secret.Data, source.Secret.Items, false)
+		return desc
+	}
+	return &ConfigSourceDescription{Kind: "Other", Source: source}
+}
+
+func configSourceWarning(desc *ConfigSourceDescription, err error) string {
+	if !kerr.IsNotFound(err) {
+		desc.err = err
+		return fmt.Sprintf("failed to read %s %q: %v", desc.Kind, desc.Name, err)
+	}
+	if desc.Optional {
+		return ""
+	}
+	return fmt.Sprintf("%s %q does not exist, database pods will not start until it is created", desc.Kind, desc.Name)
+}
+
+// configFiles lists the files a volume built from data would contain. If items are given,
+// only the projected keys are included, named after their paths.
+func configFiles(data map[string][]byte, items []core.KeyToPath, showContent bool) []ConfigFileDescription {
+	var files []ConfigFileDescription
+	add := func(name string, v []byte) {
+		file := ConfigFileDescription{Name: name, Size: len(v)}
+		if showContent {
+			file.Content = string(v)
+		}
+		files = append(files, file)
+	}
+
+	if len(items) > 0 {
+		for _, item := range items {
+			if v, ok := data[item.Key]; ok {
+				add(item.Path, v)
+			}
+		}
+	} else {
+		for k, v := range data {
+			add(k, v)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files
+}
+
+func describeConfigSource(desc *ConfigSourceDescription, w versioned.PrefixWriter) {
+	if desc == nil {
+		return
+	}
+
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "Config Source:\n")
+	if desc.Source != nil {
+		describeVolume(*desc.Source, w)
+		return
+	}
+	w.Write(LEVEL_1, "Type:\t%s\n", desc.Kind)
+	w.Write(LEVEL_1, "Name:\t%s\n", desc.Name)
+	if desc.Optional {
+		w.Write(LEVEL_1, "Optional:\ttrue\n")
+	}
+	if desc.Warning != "" {
+		w.Write(LEVEL_1, "WARNING:\t%s\n", desc.Warning)
+	}
+	if len(desc.Files) == 0 {
+		return
+	}
+	w.Write(LEVEL_1, "Files:\n")
+	for _, f := range desc.Files {
+		w.Write(LEVEL_2, "%s:\t%d bytes\n", f.Name, f.Size)
+	}
+	for _, f := range desc.Files {
+		if f.Content == "" {
+			continue
+		}
+		w.Write(LEVEL_0, "\n%s:\n----\n%s\n", f.Name, f.Content)
+	}
+}
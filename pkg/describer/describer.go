@@ -23,27 +23,23 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"text/tabwriter"
 
-	"github.com/golang/glog"
-	appsv1 "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	coreclient "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/kubernetes/pkg/kubectl/describe"
 	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
-	meta_util "kmodules.xyz/client-go/meta"
+	catalog "kubedb.dev/apimachinery/apis/catalog/v1alpha1"
 	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
 	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
-	"kubedb.dev/cli/pkg/events"
 )
 
 // Each level has 2 spaces for PrefixWriter
@@ -54,25 +50,56 @@ const (
 	LEVEL_3
 )
 
-// DescriberFn gives a way to easily override the function for unit testing if needed
-var DescriberFn describe.DescriberFunc = describer
+const (
+	// describeQPS and describeBurst raise the client side rate limit of the shared clients,
+	// which otherwise throttles describing many objects in parallel.
+	describeQPS   = 50
+	describeBurst = 100
+)
 
-// Returns a Describer for displaying the specified RESTMapping type or an error.
-func describer(restClientGetter genericclioptions.RESTClientGetter, mapping *meta.RESTMapping) (describe.Describer, error) {
-	clientConfig, err := restClientGetter.ToRESTConfig()
-	if err != nil {
-		return nil, err
-	}
-	// try to get a describer
-	if describer, ok := DescriberFor(mapping.GroupVersionKind.GroupKind(), clientConfig); ok {
-		return describer, nil
+// NewDescriberFunc returns a DescriberFunc meant for a single command invocation. The describers
+// it returns are built on first use and share their clients and object cache, so the objects
+// common to several descriptions are fetched once. The cache lives as long as the returned function.
+func NewDescriberFunc() describe.DescriberFunc {
+	var (
+		mu         sync.Mutex
+		describers map[schema.GroupKind]describe.Describer
+	)
+	return func(restClientGetter genericclioptions.RESTClientGetter, mapping *meta.RESTMapping) (describe.Describer, error) {
+		clientConfig, err := restClientGetter.ToRESTConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		mu.Lock()
+		if describers == nil {
+			describers, err = describerMap(sharedClientConfig(clientConfig))
+		}
+		m := describers
+		mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		if describer, ok := m[mapping.GroupVersionKind.GroupKind()]; ok {
+			return describer, nil
+		}
+		if genericDescriber, ok := versioned.GenericDescriberFor(mapping, clientConfig); ok {
+			return genericDescriber, nil
+		}
+		return nil, fmt.Errorf("no description has been implemented for %s", mapping.GroupVersionKind.String())
 	}
-	// if this is a kind we don't have a describer for yet, go generic if possible
-	if genericDescriber, ok := versioned.GenericDescriberFor(mapping, clientConfig); ok {
-		return genericDescriber, nil
+}
+
+// sharedClientConfig raises the client side rate limit of clientConfig, unless one is set, as the
+// clients built from it are shared by all describers of an invocation.
+func sharedClientConfig(clientConfig *rest.Config) *rest.Config {
+	clientConfig = rest.CopyConfig(clientConfig)
+	if clientConfig.QPS == 0 {
+		clientConfig.QPS = describeQPS
+		clientConfig.Burst = describeBurst
 	}
-	// otherwise return an unregistered error
-	return nil, fmt.Errorf("no description has been implemented for %s", mapping.GroupVersionKind.String())
+	return clientConfig
 }
 
 func describerMap(clientConfig *rest.Config) (map[schema.GroupKind]describe.Describer, error) {
@@ -84,62 +111,51 @@ func describerMap(clientConfig *rest.Config) (map[schema.GroupKind]describe.Desc
 	if err != nil {
 		return nil, err
 	}
+	dc, err := dynamic.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
 
+	oc := newObjectCache(c, k)
+	newDescriber := func(
+		get func(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error),
+		render func(o *describerOptions, desc *Description, w versioned.PrefixWriter),
+	) *kubedbDescriber {
+		return &kubedbDescriber{kubedb: k, dc: dc, cache: oc, get: get, render: render}
+	}
+	describeDatabase := (*describerOptions).describeDatabase
 	m := map[schema.GroupKind]describe.Describer{
-		api.Kind(api.ResourceKindEtcd):          &EtcdDescriber{c, k},
-		api.Kind(api.ResourceKindElasticsearch): &ElasticsearchDescriber{c, k},
-		api.Kind(api.ResourceKindMemcached):     &MemcachedDescriber{c, k},
-		api.Kind(api.ResourceKindMongoDB):       &MongoDBDescriber{c, k},
-		api.Kind(api.ResourceKindMySQL):         &MySQLDescriber{c, k},
-		api.Kind(api.ResourceKindPostgres):      &PostgresDescriber{c, k},
-		api.Kind(api.ResourceKindRedis):         &RedisDescriber{c, k},
+		api.Kind(api.ResourceKindEtcd):            newDescriber(getEtcd, describeDatabase),
+		api.Kind(api.ResourceKindElasticsearch):   newDescriber(getElasticsearch, describeDatabase),
+		api.Kind(api.ResourceKindMariaDB):         newDescriber(getMariaDB, describeDatabase),
+		api.Kind(api.ResourceKindMemcached):       newDescriber(getMemcached, describeDatabase),
+		api.Kind(api.ResourceKindMongoDB):         newDescriber(getMongoDB, describeDatabase),
+		api.Kind(api.ResourceKindMySQL):           newDescriber(getMySQL, describeDatabase),
+		api.Kind(api.ResourceKindPerconaXtraDB):   newDescriber(getPerconaXtraDB, describeDatabase),
+		api.Kind(api.ResourceKindPostgres):        newDescriber(getPostgres, describeDatabase),
+		api.Kind(api.ResourceKindRedis):           newDescriber(getRedis, describeDatabase),
+		api.Kind(api.ResourceKindSnapshot):        newDescriber(getSnapshot, (*describerOptions).describeSnapshot),
+		api.Kind(api.ResourceKindDormantDatabase): newDescriber(getDormantDatabase, (*describerOptions).describeDormantDatabase),
+
+		catalog.Kind(catalog.ResourceKindElasticsearchVersion): &CatalogVersionDescriber{dc: dc, kind: api.ResourceKindElasticsearch},
+		catalog.Kind(catalog.ResourceKindEtcdVersion):          &CatalogVersionDescriber{dc: dc, kind: api.ResourceKindEtcd},
+		catalog.Kind(catalog.ResourceKindMemcachedVersion):     &CatalogVersionDescriber{dc: dc, kind: api.ResourceKindMemcached},
+		catalog.Kind(catalog.ResourceKindMongoDBVersion):       &CatalogVersionDescriber{dc: dc, kind: api.ResourceKindMongoDB},
+		catalog.Kind(catalog.ResourceKindMySQLVersion):         &CatalogVersionDescriber{dc: dc, kind: api.ResourceKindMySQL},
+		catalog.Kind(catalog.ResourceKindPerconaXtraDBVersion): &CatalogVersionDescriber{dc: dc, kind: api.ResourceKindPerconaXtraDB},
+		catalog.Kind(catalog.ResourceKindPostgresVersion):      &CatalogVersionDescriber{dc: dc, kind: api.ResourceKindPostgres},
+		catalog.Kind(catalog.ResourceKindRedisVersion):         &CatalogVersionDescriber{dc: dc, kind: api.ResourceKindRedis},
 	}
 
 	return m, nil
 }
 
-// DescriberFor returns the default describe functions for each of the standard
-// Kubernetes types.
-func DescriberFor(kind schema.GroupKind, clientConfig *rest.Config) (describe.Describer, bool) {
-	describers, err := describerMap(clientConfig)
-	if err != nil {
-		glog.V(1).Info(err)
-		return nil, false
-	}
-
-	f, ok := describers[kind]
-	return f, ok
-}
-
-func describeStatefulSet(ps *appsv1.StatefulSet, running, waiting, succeeded, failed int, w versioned.PrefixWriter) {
-	w.Write(LEVEL_0, "\n")
-	w.Write(LEVEL_0, "StatefulSet:\t\n")
-	w.Write(LEVEL_1, "Name:\t%s\n", ps.ObjectMeta.Name)
-	w.Write(LEVEL_1, "CreationTimestamp:\t%s\n", timeToString(&ps.CreationTimestamp))
-	printLabelsMultiline(LEVEL_1, w, "Labels", ps.Labels)
-	printAnnotationsMultiline(LEVEL_1, w, "Annotations", ps.Annotations)
-	w.Write(LEVEL_1, "Replicas:\t%d desired | %d total\n", ps.Spec.Replicas, ps.Status.Replicas)
-	w.Write(LEVEL_1, "Pods Status:\t%d Running / %d Waiting / %d Succeeded / %d Failed\n", running, waiting, succeeded, failed)
-}
-
-func describeDeployment(d *appsv1.Deployment, running, waiting, succeeded, failed int, w versioned.PrefixWriter) {
-	w.Write(LEVEL_0, "\n")
-	w.Write(LEVEL_0, "Deployment:\t\n")
-	w.Write(LEVEL_1, "Name:\t%s\n", d.ObjectMeta.Name)
-	w.Write(LEVEL_1, "CreationTimestamp:\t%s\n", timeToString(&d.CreationTimestamp))
-	printLabelsMultiline(LEVEL_1, w, "Labels", d.Labels)
-	printAnnotationsMultiline(LEVEL_1, w, "Annotations", d.Annotations)
-	w.Write(LEVEL_1, "Replicas:\t%d desired | %d updated | %d total | %d available | %d unavailable\n", *(d.Spec.Replicas), d.Status.UpdatedReplicas, d.Status.Replicas, d.Status.AvailableReplicas, d.Status.UnavailableReplicas)
-	w.Write(LEVEL_1, "Pods Status:\t%d Running / %d Waiting / %d Succeeded / %d Failed\n", running, waiting, succeeded, failed)
-}
-
-func getPodStatusForController(c coreclient.PodInterface, selector labels.Selector) (running, waiting, succeeded, failed int, err error) {
-	options := metav1.ListOptions{LabelSelector: selector.String()}
-	rcPods, err := c.List(options)
+func getPodStatusForController(cache *objectCache, namespace string, selector labels.Selector) (running, waiting, succeeded, failed int, err error) {
+	rcPods, err := cache.pods(namespace, selector)
 	if err != nil {
 		return
 	}
-	for _, pod := range rcPods.Items {
+	for _, pod := range rcPods {
 		switch pod.Status.Phase {
 		case core.PodRunning:
 			running++
@@ -170,76 +186,6 @@ func buildIngressString(ingress []core.LoadBalancerIngress) string {
 	return buffer.String()
 }
 
-func describeService(service *core.Service, endpoints *core.Endpoints, w versioned.PrefixWriter) {
-	if endpoints == nil {
-		endpoints = &core.Endpoints{}
-	}
-	w.Write(LEVEL_0, "\n")
-	w.Write(LEVEL_0, "Service:\t\n")
-	w.Write(LEVEL_1, "Name:\t%s\n", service.Name)
-	printLabelsMultiline(LEVEL_1, w, "Labels", service.Labels)
-	printAnnotationsMultiline(LEVEL_1, w, "Annotations", service.Annotations)
-	w.Write(LEVEL_1, "Type:\t%s\n", service.Spec.Type)
-	w.Write(LEVEL_1, "IP:\t%s\n", service.Spec.ClusterIP)
-	if len(service.Spec.ExternalIPs) > 0 {
-		w.Write(LEVEL_1, "External IPs:\t%v\n", strings.Join(service.Spec.ExternalIPs, ","))
-	}
-	if service.Spec.LoadBalancerIP != "" {
-		w.Write(LEVEL_1, "IP:\t%s\n", service.Spec.LoadBalancerIP)
-	}
-	if service.Spec.ExternalName != "" {
-		w.Write(LEVEL_1, "External Name:\t%s\n", service.Spec.ExternalName)
-	}
-	if len(service.Status.LoadBalancer.Ingress) > 0 {
-		list := buildIngressString(service.Status.LoadBalancer.Ingress)
-		w.Write(LEVEL_1, "LoadBalancer Ingress:\t%s\n", list)
-	}
-	for i := range service.Spec.Ports {
-		sp := &service.Spec.Ports[i]
-
-		name := sp.Name
-		if name == "" {
-			name = "<unset>"
-		}
-		w.Write(LEVEL_1, "Port:\t%s\t%d/%s\n", name, sp.Port, sp.Protocol)
-		if sp.TargetPort.Type == intstr.Type(intstr.Int) {
-			w.Write(LEVEL_1, "TargetPort:\t%d/%s\n", sp.TargetPort.IntVal, sp.Protocol)
-		} else {
-			w.Write(LEVEL_1, "TargetPort:\t%s/%s\n", sp.TargetPort.StrVal, sp.Protocol)
-		}
-		if sp.NodePort != 0 {
-			w.Write(LEVEL_1, "NodePort:\t%s\t%d/%s\n", name, sp.NodePort, sp.Protocol)
-		}
-		w.Write(LEVEL_1, "Endpoints:\t%s\n", formatEndpoints(endpoints, sets.NewString(sp.Name)))
-	}
-}
-
-// describeSecret generates information about a secret
-func describeSecret(secret *core.Secret, prefix string, w versioned.PrefixWriter) {
-	w.Write(LEVEL_0, "\n")
-	if prefix == "" {
-		w.Write(LEVEL_0, "Secret:\n")
-	} else {
-		w.Write(LEVEL_0, "%s Secret:\n", prefix)
-	}
-	w.Write(LEVEL_1, "Name:\t%s\n", secret.Name)
-	printLabelsMultiline(LEVEL_1, w, "Labels", secret.Labels)
-	skipAnnotations := sets.NewString(meta_util.LastAppliedConfigAnnotation)
-	printAnnotationsMultilineWithFilter(LEVEL_1, w, "Annotations", secret.Annotations, skipAnnotations)
-
-	w.Write(LEVEL_1, "\nType:\t%s\n", secret.Type)
-
-	w.Write(LEVEL_1, "\nData\n====\n")
-	for k, v := range secret.Data {
-		switch {
-		case k == core.ServiceAccountTokenKey && secret.Type == core.SecretTypeServiceAccountToken:
-			w.Write(LEVEL_1, "%s:\t%s\n", k, string(v))
-		default:
-			w.Write(LEVEL_1, "%s:\t%d bytes\n", k, len(v))
-		}
-	}
-}
-
 func describeVolume(volume core.VolumeSource, w versioned.PrefixWriter) {
 	w.Write(LEVEL_0, "Volume:\n")
 	switch {
@@ -545,33 +491,6 @@ func printFlockerVolumeSource(flocker *core.FlockerVolumeSource, w versioned.Pre
 		flocker.DatasetName, flocker.DatasetUUID)
 }
 
-func DescribeEvents(el *core.EventList, w versioned.PrefixWriter) {
-	w.Write(LEVEL_0, "\n")
-	if len(el.Items) == 0 {
-		w.Write(LEVEL_0, "Events:\t<none>\n")
-		return
-	}
-	w.Flush()
-	sort.Sort(events.SortableEvents(el.Items))
-	w.Write(LEVEL_0, "Events:\n  Type\tReason\tAge\tFrom\tMessage\n")
-	w.Write(LEVEL_1, "----\t------\t----\t----\t-------\n")
-	for _, e := range el.Items {
-		var interval string
-		if e.Count > 1 {
-			interval = fmt.Sprintf("%s (x%d over %s)", translateTimestamp(e.LastTimestamp), e.Count, translateTimestamp(e.FirstTimestamp))
-		} else {
-			interval = translateTimestamp(e.FirstTimestamp)
-		}
-		w.Write(LEVEL_1, "%v\t%v\t%s\t%v\t%v\n",
-			e.Type,
-			e.Reason,
-			interval,
-			formatEventSource(e.Source),
-			strings.TrimSpace(e.Message),
-		)
-	}
-}
-
 var maxAnnotationLen = 200
 
 // printLabelsMultiline prints multiple labels with a proper alignment.
@@ -0,0 +1,345 @@
+package describer
+
+import (
+	"fmt"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kubernetes/pkg/kubectl/describe"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
+	mona "kmodules.xyz/monitoring-agent-api/api/v1"
+	store "kmodules.xyz/objectstore-api/api/v1"
+	ofst "kmodules.xyz/offshoot-api/api/v1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+// ObjectDescriber is implemented by the describers that can produce a structured
+// Description in addition to the tabbed text returned by Describe.
+type ObjectDescriber interface {
+	DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error)
+}
+
+// Description holds the information gathered by describe for a single object. Describe renders
+// it as tabbed text, DescribeObject returns it as is. Sections that do not apply to the kind of
+// the object are left empty.
+type Description struct {
+	Kind              string      `json:"kind"`
+	Name              string      `json:"name"`
+	Namespace         string      `json:"namespace,omitempty"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	// CompletionTimestamp is the time a snapshot completed.
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+	// PausedTimestamp and WipeOutTimestamp are the times a dormant database was paused and wiped out.
+	PausedTimestamp  *metav1.Time      `json:"pausedTimestamp,omitempty"`
+	WipeOutTimestamp *metav1.Time      `json:"wipeOutTimestamp,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	Annotations      map[string]string `json:"annotations,omitempty"`
+	Status           string            `json:"status,omitempty"`
+	Reason           string            `json:"reason,omitempty"`
+	Spec             *SpecSummary      `json:"spec,omitempty"`
+
+	CatalogVersion *CatalogVersion `json:"catalogVersion,omitempty"`
+	// VersionWarning is set if the catalog version could not be resolved or is deprecated.
+	VersionWarning string `json:"versionWarning,omitempty"`
+
+	PXC      *PXCDescription `json:"pxc,omitempty"`
+	Archiver *store.Backend  `json:"archiver,omitempty"`
+	Init     *api.InitSpec   `json:"init,omitempty"`
+	// Backend is where a snapshot is stored.
+	Backend *store.Backend `json:"backend,omitempty"`
+
+	VolumeClaims     []VolumeClaimDescription     `json:"volumeClaims,omitempty"`
+	ConfigSource     *ConfigSourceDescription     `json:"configSource,omitempty"`
+	Workloads        []WorkloadDescription        `json:"workloads,omitempty"`
+	Services         []ServiceDescription         `json:"services,omitempty"`
+	PodTemplate      *PodTemplateDescription      `json:"podTemplate,omitempty"`
+	ServiceTemplates []ServiceTemplateDescription `json:"serviceTemplates,omitempty"`
+	Secrets          []SecretDescription          `json:"secrets,omitempty"`
+	TLS              *TLSDescription              `json:"tls,omitempty"`
+	AppBinding       *AppBindingDescription       `json:"appBinding,omitempty"`
+
+	MySQLCluster *api.MySQLClusterTopology `json:"mysqlCluster,omitempty"`
+	// ReplicaSet is the name of the replica set of a MongoDB.
+	ReplicaSet    string                           `json:"replicaSet,omitempty"`
+	Topology      *TopologyDescription             `json:"topology,omitempty"`
+	NodeGroups    []NodeGroupDescription           `json:"nodeGroups,omitempty"`
+	PostgresHA    *PostgresHADescription           `json:"postgresHA,omitempty"`
+	ShardTopology *MongoDBShardTopologyDescription `json:"shardTopology,omitempty"`
+	RedisCluster  *RedisClusterDescription         `json:"redisCluster,omitempty"`
+
+	Monitor        *mona.AgentSpec            `json:"monitor,omitempty"`
+	BackupSchedule *BackupScheduleDescription `json:"backupSchedule,omitempty"`
+	Origin         *OriginDescription         `json:"origin,omitempty"`
+	// Databases are the databases using a catalog version.
+	Databases []CatalogDatabaseDescription `json:"databases,omitempty"`
+	// Snapshots is nil if the snapshots were not listed.
+	Snapshots []SnapshotDescription `json:"snapshots,omitempty"`
+	// Events is nil if events were not requested.
+	Events []EventDescription `json:"events,omitempty"`
+
+	// Warnings lists the parts of the description that could not be collected.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type SpecSummary struct {
+	Version           string                `json:"version"`
+	Replicas          *int32                `json:"replicas,omitempty"`
+	StorageType       api.StorageType       `json:"storageType,omitempty"`
+	Storage           *StorageSummary       `json:"storage,omitempty"`
+	TerminationPolicy api.TerminationPolicy `json:"terminationPolicy,omitempty"`
+}
+
+type StorageSummary struct {
+	StorageClass string `json:"storageClass,omitempty"`
+	Capacity     string `json:"capacity"`
+	AccessModes  string `json:"accessModes,omitempty"`
+}
+
+type PXCDescription struct {
+	ClusterName      string `json:"clusterName,omitempty"`
+	ProxysqlReplicas *int32 `json:"proxysqlReplicas,omitempty"`
+}
+
+type SnapshotDescription struct {
+	Name           string       `json:"name"`
+	Location       string       `json:"location"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	Phase          string       `json:"phase,omitempty"`
+}
+
+type OriginDescription struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// database carries the parts of a KubeDB database object that are common to all kinds.
+type database struct {
+	obj      runtime.Object
+	meta     metav1.ObjectMeta
+	kind     string
+	phase    api.DatabasePhase
+	reason   string
+	spec     SpecSummary
+	selector labels.Selector
+	secrets  map[string]*core.SecretVolumeSource
+	monitor  *mona.AgentSpec
+	app      appcat.AppBindingMeta
+	schedule *api.BackupScheduleSpec
+	storage  *core.PersistentVolumeClaimSpec
+	config   *core.VolumeSource
+	// podTemplate and serviceTemplates are compared against the live workloads and services.
+	podTemplate      *ofst.PodTemplateSpec
+	serviceTemplates []serviceTemplate
+	// tls holds the TLS settings of the database, nil if TLS is not enabled. Its secrets are read from certs.
+	tls   *TLSDescription
+	certs []certificateSecret
+}
+
+// getDatabase collects the sections common to all database kinds. Sub-queries that fail are
+// recorded as warnings of the description.
+func (o *describerOptions) getDatabase(cache *objectCache, dc dynamic.Interface, db database, describerSettings describe.DescriberSettings) (*Description, error) {
+	namespace := db.meta.Namespace
+	desc := &Description{
+		Kind:              db.kind,
+		Name:              db.meta.Name,
+		Namespace:         namespace,
+		CreationTimestamp: db.meta.CreationTimestamp,
+		Labels:            db.meta.Labels,
+		Annotations:       db.meta.Annotations,
+		Status:            string(db.phase),
+		Reason:            db.reason,
+		Spec:              &db.spec,
+		Monitor:           db.monitor,
+	}
+
+	if HasCatalogVersion(db.kind) {
+		getDatabaseVersion(dc, db.kind, db.spec.Version, desc)
+	}
+
+	if db.spec.StorageType != "" {
+		claims, err := getVolumeClaims(cache, namespace, db.selector, db.storage)
+		if err != nil {
+			desc.warn("PersistentVolumeClaims", err)
+		}
+		desc.VolumeClaims = claims
+	}
+
+	desc.ConfigSource = getConfigSource(cache, namespace, db.config, o.showConfig)
+	if cfg := desc.ConfigSource; cfg != nil && cfg.err != nil {
+		desc.warn(fmt.Sprintf("config source %s %s", cfg.Kind, cfg.Name), cfg.err)
+	}
+
+	statefulSets, err := cache.statefulSets(namespace, db.selector)
+	if err != nil {
+		desc.warn("StatefulSets", err)
+	}
+	deployments, err := cache.deployments(namespace, db.selector)
+	if err != nil {
+		desc.warn("Deployments", err)
+	}
+	desc.Workloads = getWorkloads(cache, namespace, statefulSets, deployments, desc)
+	desc.Services = getServices(cache, namespace, db.selector, desc)
+	desc.PodTemplate = getPodTemplate(db.podTemplate, statefulSets, deployments)
+	for _, st := range db.serviceTemplates {
+		desc.ServiceTemplates = append(desc.ServiceTemplates, getServiceTemplate(cache, namespace, st, desc))
+	}
+
+	desc.Secrets = getSecrets(cache, namespace, db.secrets, desc)
+	if db.tls != nil {
+		desc.TLS = db.tls
+		desc.TLS.Secrets = getCertificateSecrets(cache, namespace, db.certs, desc)
+	}
+
+	if app, err := GetAppBinding(dc, namespace, db.app.Name()); err == nil {
+		desc.AppBinding = app
+	} else {
+		desc.warnUnlessNotFound(fmt.Sprintf("AppBinding %s", db.app.Name()), err)
+	}
+
+	snapshots, err := cache.snapshots(namespace, db.selector)
+	if err != nil {
+		desc.warn("Snapshots", err)
+	}
+	desc.BackupSchedule = summarizeBackupSchedule(db.schedule, snapshots, time.Now())
+	if snapshots != nil {
+		desc.Snapshots = getSnapshots(snapshots)
+	}
+
+	if describerSettings.ShowEvents {
+		if el, err := searchEvents(cache, db.obj, db.selector, o.deepEvents, o.eventsType); err == nil {
+			desc.Events = getEvents(el, o.deepEvents)
+		} else {
+			desc.warn("Events", err)
+		}
+	}
+
+	return desc, nil
+}
+
+func getSnapshots(snapshots []api.Snapshot) []SnapshotDescription {
+	list := make([]SnapshotDescription, 0, len(snapshots))
+	for _, s := range snapshots {
+		location, err := s.Spec.Backend.Location()
+		if err != nil {
+			location = "<invalid>"
+		}
+		list = append(list, SnapshotDescription{
+			Name:           s.Name,
+			Location:       location,
+			StartTime:      s.Status.StartTime,
+			CompletionTime: s.Status.CompletionTime,
+			Phase:          string(s.Status.Phase),
+		})
+	}
+	return list
+}
+
+// specSummary returns the summary of the spec of a database. Databases backed by volumes are
+// Durable unless their storage type says otherwise.
+func specSummary(version string, replicas *int32, storageType api.StorageType, storage *core.PersistentVolumeClaimSpec, policy api.TerminationPolicy) SpecSummary {
+	if storageType != api.StorageTypeEphemeral {
+		storageType = api.StorageTypeDurable
+	}
+	return SpecSummary{
+		Version:           version,
+		Replicas:          replicas,
+		StorageType:       storageType,
+		Storage:           summarizeStorage(storage),
+		TerminationPolicy: policy,
+	}
+}
+
+func summarizeStorage(pvcSpec *core.PersistentVolumeClaimSpec) *StorageSummary {
+	if pvcSpec == nil {
+		return nil
+	}
+	val, _ := pvcSpec.Resources.Requests[core.ResourceStorage]
+	summary := &StorageSummary{
+		Capacity:    val.String(),
+		AccessModes: getAccessModesAsString(pvcSpec.AccessModes),
+	}
+	if pvcSpec.StorageClassName != nil {
+		summary.StorageClass = *pvcSpec.StorageClassName
+	}
+	return summary
+}
+
+func databaseSecrets(databaseSecret, certificateSecret *core.SecretVolumeSource) map[string]*core.SecretVolumeSource {
+	secretVolumes := make(map[string]*core.SecretVolumeSource)
+	if databaseSecret != nil {
+		secretVolumes["Database"] = databaseSecret
+	}
+	if certificateSecret != nil {
+		secretVolumes["Certificate"] = certificateSecret
+	}
+	return secretVolumes
+}
+
+// describeDatabase renders the description of a database of any kind.
+func (o *describerOptions) describeDatabase(desc *Description, w versioned.PrefixWriter) {
+	w.Write(LEVEL_0, "Name:\t%s\n", desc.Name)
+	w.Write(LEVEL_0, "Namespace:\t%s\n", desc.Namespace)
+	w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&desc.CreationTimestamp))
+	printLabelsMultiline(LEVEL_0, w, "Labels", desc.Labels)
+	printAnnotationsMultiline(LEVEL_0, w, "Annotations", desc.Annotations)
+
+	if desc.Spec.Replicas != nil {
+		w.Write(LEVEL_0, "Replicas:\t%d  total\n", *desc.Spec.Replicas)
+	}
+	w.Write(LEVEL_0, "Status:\t%s\n", desc.Status)
+	if len(desc.Reason) > 0 {
+		w.Write(LEVEL_0, "Reason:\t%s\n", desc.Reason)
+	}
+	describeDatabaseVersion(desc, w)
+
+	describePXC(desc.PXC, w)
+	describeArchiver(desc.Archiver, w)
+	describeInitialization(desc.Init, w)
+
+	if desc.Spec.StorageType != "" {
+		describeStorage(desc.Spec.StorageType, desc.Spec.Storage, w)
+		describeVolumeClaims(desc.VolumeClaims, w)
+	}
+
+	describeConfigSource(desc.ConfigSource, w)
+	describeWorkloads(desc.Workloads, w)
+	describeServices(desc.Services, w)
+	describePodTemplate(desc.PodTemplate, w)
+	for i := range desc.ServiceTemplates {
+		describeServiceTemplate(&desc.ServiceTemplates[i], w)
+	}
+	describeSecrets(desc.Secrets, w)
+	describeTLS(desc.TLS, w)
+	describeAppBinding(desc.AppBinding, w)
+
+	if desc.MySQLCluster != nil {
+		describeMySQLTopology(desc.MySQLCluster, w)
+	}
+	if desc.ReplicaSet != "" {
+		w.Write(LEVEL_0, "\n")
+		w.Write(LEVEL_0, "ReplicaSet:\n")
+		w.Write(LEVEL_1, "Name:\t%s\n", desc.ReplicaSet)
+	}
+	describeTopology(LEVEL_0, desc.Topology, w)
+	describeElasticsearchTopology(desc.NodeGroups, w)
+	describePostgresHA(desc.PostgresHA, w)
+	describeMongoDBShardTopology(desc.ShardTopology, w)
+	describeRedisCluster(desc.RedisCluster, w)
+
+	describeMonitor(desc.Monitor, w)
+	describeBackupSchedule(desc.BackupSchedule, w)
+	if desc.Snapshots != nil {
+		listSnapshots(desc.Snapshots, w)
+	}
+	if desc.Events != nil {
+		o.describeEvents(desc.Events, w)
+	}
+}
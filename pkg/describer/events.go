@@ -0,0 +1,229 @@
+package describer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/databases"
+	"kubedb.dev/cli/pkg/events"
+)
+
+// EventsDescriber is implemented by the describers of database kinds, whose events can be
+// merged with the events of the objects created for them.
+type EventsDescriber interface {
+	// DeepEvents includes the events of the pods, workloads, volume claims, services, jobs and
+	// snapshots matched by the offshoot selectors of the database. For a snapshot, the events of
+	// its backup job and the pods of the job are included.
+	DeepEvents(deep bool)
+	// EventsType only includes the events of the given type, i.e. Warning. All events are included if empty.
+	EventsType(eventType string)
+}
+
+func (o *describerOptions) DeepEvents(deep bool) {
+	o.deepEvents = deep
+}
+
+func (o *describerOptions) EventsType(eventType string) {
+	o.eventsType = eventType
+}
+
+// searchEvents returns the events of obj, sorted by time. If deep is set, the events of the objects
+// matched by selector are included as well.
+func searchEvents(cache *objectCache, obj runtime.Object, selector labels.Selector, deep bool, eventType string) (*core.EventList, error) {
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	uids := map[types.UID]bool{objMeta.GetUID(): true}
+	if deep {
+		if err := ownedUIDs(cache, objMeta.GetNamespace(), selector, uids); err != nil {
+			return nil, err
+		}
+	}
+	return listEvents(cache, objMeta.GetNamespace(), uids, eventType)
+}
+
+// listEvents returns the events of the objects with the given uids, sorted by time.
+func listEvents(cache *objectCache, namespace string, uids map[types.UID]bool, eventType string) (*core.EventList, error) {
+	el := &core.EventList{}
+	for uid := range uids {
+		items, err := cache.events(namespace, uid)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range items {
+			if eventType == "" || e.Type == eventType {
+				el.Items = append(el.Items, e)
+			}
+		}
+	}
+	sort.Sort(events.SortableEvents(el.Items))
+	return el, nil
+}
+
+// ownedUIDs adds the UIDs of the pods, workloads, volume claims, services, jobs and snapshots
+// matched by the offshoot selectors of a database to uids.
+func ownedUIDs(cache *objectCache, namespace string, selector labels.Selector, uids map[types.UID]bool) error {
+	pods, err := cache.pods(namespace, selector)
+	if err != nil {
+		return err
+	}
+	for _, o := range pods {
+		uids[o.UID] = true
+	}
+	statefulSets, err := cache.statefulSets(namespace, selector)
+	if err != nil {
+		return err
+	}
+	for _, o := range statefulSets {
+		uids[o.UID] = true
+	}
+	deployments, err := cache.deployments(namespace, selector)
+	if err != nil {
+		return err
+	}
+	for _, o := range deployments {
+		uids[o.UID] = true
+	}
+	claims, err := cache.persistentVolumeClaims(namespace, selector)
+	if err != nil {
+		return err
+	}
+	for _, o := range claims {
+		uids[o.UID] = true
+	}
+	services, err := cache.services(namespace, selector)
+	if err != nil {
+		return err
+	}
+	for _, o := range services {
+		uids[o.UID] = true
+	}
+	jobs, err := cache.jobs(namespace, selector)
+	if err != nil {
+		return err
+	}
+	for _, o := range jobs {
+		uids[o.UID] = true
+	}
+	snapshots, err := cache.snapshots(namespace, selector)
+	if err != nil {
+		return err
+	}
+	for _, o := range snapshots {
+		uids[o.UID] = true
+	}
+	return nil
+}
+
+// snapshotUIDs adds the UIDs of the backup job of snapshot and of the pods of the job to uids.
+func snapshotUIDs(cache *objectCache, snapshot *api.Snapshot, uids map[types.UID]bool) error {
+	selector := databases.SnapshotJobSelector(snapshot)
+	if selector == nil {
+		return nil
+	}
+	jobs, err := cache.jobs(snapshot.Namespace, selector)
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		job := &jobs[i]
+		if !databases.IsSnapshotJob(job, snapshot) {
+			continue
+		}
+		uids[job.UID] = true
+		if job.Spec.Selector == nil {
+			continue
+		}
+		podSelector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err != nil {
+			return err
+		}
+		pods, err := cache.pods(snapshot.Namespace, podSelector)
+		if err != nil {
+			return err
+		}
+		for _, o := range pods {
+			uids[o.UID] = true
+		}
+	}
+	return nil
+}
+
+type EventDescription struct {
+	Type           string      `json:"type"`
+	Reason         string      `json:"reason"`
+	Count          int32       `json:"count"`
+	FirstTimestamp metav1.Time `json:"firstTimestamp"`
+	LastTimestamp  metav1.Time `json:"lastTimestamp"`
+	From           string      `json:"from"`
+	Message        string      `json:"message"`
+	// Object is the object the event is about. It is only set for deep events.
+	Object string `json:"object,omitempty"`
+}
+
+// getEvents summarizes the events found by searchEvents. The result is never nil.
+func getEvents(el *core.EventList, deep bool) []EventDescription {
+	list := make([]EventDescription, 0, len(el.Items))
+	for _, e := range el.Items {
+		ed := EventDescription{
+			Type:           e.Type,
+			Reason:         e.Reason,
+			Count:          e.Count,
+			FirstTimestamp: e.FirstTimestamp,
+			LastTimestamp:  e.LastTimestamp,
+			From:           formatEventSource(e.Source),
+			Message:        strings.TrimSpace(e.Message),
+		}
+		if deep {
+			ed.Object = eventObject(e)
+		}
+		list = append(list, ed)
+	}
+	return list
+}
+
+// eventObject returns the object an event is about, i.e. Pod/postgres-0.
+func eventObject(e core.Event) string {
+	return fmt.Sprintf("%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Name)
+}
+
+// describeEvents prints the events of an object. The object each event is about is
+// included if they were collected from the objects created for a database too.
+func (o *describerOptions) describeEvents(events []EventDescription, w versioned.PrefixWriter) {
+	w.Write(LEVEL_0, "\n")
+	if len(events) == 0 {
+		w.Write(LEVEL_0, "Events:\t<none>\n")
+		return
+	}
+	w.Flush()
+	if o.deepEvents {
+		w.Write(LEVEL_0, "Events:\n  Type\tReason\tAge\tFrom\tObject\tMessage\n")
+		w.Write(LEVEL_1, "----\t------\t----\t----\t------\t-------\n")
+	} else {
+		w.Write(LEVEL_0, "Events:\n  Type\tReason\tAge\tFrom\tMessage\n")
+		w.Write(LEVEL_1, "----\t------\t----\t----\t-------\n")
+	}
+	for _, e := range events {
+		var interval string
+		if e.Count > 1 {
+			interval = fmt.Sprintf("%s (x%d over %s)", translateTimestamp(e.LastTimestamp), e.Count, translateTimestamp(e.FirstTimestamp))
+		} else {
+			interval = translateTimestamp(e.FirstTimestamp)
+		}
+		if o.deepEvents {
+			w.Write(LEVEL_1, "%v\t%v\t%s\t%v\t%s\t%v\n", e.Type, e.Reason, interval, e.From, e.Object, e.Message)
+		} else {
+			w.Write(LEVEL_1, "%v\t%v\t%s\t%v\t%v\n", e.Type, e.Reason, interval, e.From, e.Message)
+		}
+	}
+}
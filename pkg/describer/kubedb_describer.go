@@ -1,718 +1,1078 @@
 package describer
 
 import (
-	"io"
+	"fmt"
 	"strings"
 
 	"github.com/appscode/go/types"
 	core "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/kubernetes"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/kubernetes/pkg/kubectl/describe"
 	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
 	mona "kmodules.xyz/monitoring-agent-api/api/v1"
 	store "kmodules.xyz/objectstore-api/api/v1"
 	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
-	"kubedb.dev/apimachinery/client/clientset/versioned/scheme"
 	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha1"
 )
 
-type EtcdDescriber struct {
-	client kubernetes.Interface
+// kubedbDescriber describes the objects of a KubeDB kind. The kinds differ only in how an object
+// is turned into a Description, which get does, and in how the Description is rendered as text.
+type kubedbDescriber struct {
 	kubedb cs.KubedbV1alpha1Interface
+	dc     dynamic.Interface
+	cache  *objectCache
+	describerOptions
+
+	get    func(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error)
+	render func(o *describerOptions, desc *Description, w versioned.PrefixWriter)
 }
 
-func (d *EtcdDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
-	item, err := d.kubedb.Etcds(namespace).Get(name, metav1.GetOptions{})
+func (d *kubedbDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+	desc, err := d.get(d, namespace, name, describerSettings)
 	if err != nil {
 		return "", err
 	}
+	return describeWithWarnings(d.strict, desc, func(w versioned.PrefixWriter) {
+		d.render(&d.describerOptions, desc, w)
+	})
+}
 
-	selector := labels.SelectorFromSet(item.OffshootSelectors())
+func (d *kubedbDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	desc, err := d.get(d, namespace, name, describerSettings)
+	if err != nil {
+		return nil, err
+	}
+	return desc, incompleteDescription(d.strict, desc)
+}
+
+func getEtcd(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.Etcds(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
 
-	snapshots, err := d.kubedb.Snapshots(item.Namespace).List(
-		metav1.ListOptions{
-			LabelSelector: selector.String(),
+	return d.getDatabase(d.cache, d.dc, database{
+		obj:         item,
+		meta:        item.ObjectMeta,
+		kind:        api.ResourceKindEtcd,
+		phase:       item.Status.Phase,
+		reason:      item.Status.Reason,
+		spec:        specSummary(string(item.Spec.Version), item.Spec.Replicas, item.Spec.StorageType, item.Spec.Storage, item.Spec.TerminationPolicy),
+		selector:    labels.SelectorFromSet(item.OffshootSelectors()),
+		secrets:     databaseSecrets(item.Spec.DatabaseSecret, nil),
+		monitor:     item.Spec.Monitor,
+		app:         item.AppBindingMeta(),
+		schedule:    item.Spec.BackupSchedule,
+		storage:     item.Spec.Storage,
+		podTemplate: &item.Spec.PodTemplate,
+		serviceTemplates: []serviceTemplate{
+			{title: "Service Template", service: item.ClientServiceName(), template: item.Spec.ServiceTemplate},
 		},
-	)
+		tls:   etcdTLS(item),
+		certs: etcdCertificateSecrets(item),
+	}, describerSettings)
+}
+
+func getElasticsearch(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.Elasticsearches(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var events *core.EventList
-	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
-		if err != nil {
-			return "", err
-		}
+	selector := labels.SelectorFromSet(item.OffshootSelectors())
+	desc, err := d.getDatabase(d.cache, d.dc, database{
+		obj:         item,
+		meta:        item.ObjectMeta,
+		kind:        api.ResourceKindElasticsearch,
+		phase:       item.Status.Phase,
+		reason:      item.Status.Reason,
+		spec:        specSummary(string(item.Spec.Version), item.Spec.Replicas, item.Spec.StorageType, item.Spec.Storage, item.Spec.TerminationPolicy),
+		selector:    selector,
+		secrets:     databaseSecrets(item.Spec.DatabaseSecret, item.Spec.CertificateSecret),
+		monitor:     item.Spec.Monitor,
+		app:         item.AppBindingMeta(),
+		schedule:    item.Spec.BackupSchedule,
+		storage:     item.Spec.Storage,
+		config:      item.Spec.ConfigSource,
+		podTemplate: &item.Spec.PodTemplate,
+		serviceTemplates: []serviceTemplate{
+			{title: "Service Template", service: item.ServiceName(), template: item.Spec.ServiceTemplate},
+		},
+		tls:   elasticsearchTLS(item),
+		certs: elasticsearchCertificateSecrets(item),
+	}, describerSettings)
+	if err != nil {
+		return nil, err
 	}
+	desc.Init = item.Spec.Init
 
-	return d.describeEtcd(item, selector, snapshots, events)
-}
+	specific := map[string]labels.Selector{
+		"master": labels.SelectorFromSet(map[string]string{"node.role.master": "set"}),
+		"client": labels.SelectorFromSet(map[string]string{"node.role.client": "set"}),
+		"data":   labels.SelectorFromSet(map[string]string{"node.role.data": "set"}),
+	}
+	desc.Topology = getTopology(d.cache, item.Namespace, "Topology", selector, specific, desc)
 
-func (d *EtcdDescriber) describeEtcd(item *api.Etcd, selector labels.Selector, snapshots *api.SnapshotList, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := versioned.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
+	if item.Spec.Topology != nil {
+		desc.NodeGroups = getElasticsearchNodeGroups(d.cache, item, desc)
+	}
+	return desc, nil
+}
 
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", types.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-		if len(item.Status.Reason) > 0 {
-			w.Write(LEVEL_0, "Reason:\t%s\n", item.Status.Reason)
-		}
+// NodeGroupDescription describes a node group of an Elasticsearch cluster with a dedicated topology.
+type NodeGroupDescription struct {
+	Role           string              `json:"role"`
+	Prefix         string              `json:"prefix,omitempty"`
+	Replicas       *int32              `json:"replicas,omitempty"`
+	Capacity       string              `json:"capacity,omitempty"`
+	Requests       core.ResourceList   `json:"requests,omitempty"`
+	Limits         core.ResourceList   `json:"limits,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	StatefulSets        []NodeStatefulSetDescription    `json:"statefulSets,omitempty"`
+	PodDisruptionBudget *PodDisruptionBudgetDescription `json:"podDisruptionBudget,omitempty"`
+	// Warning is set if the PodDisruptionBudget is missing or does not honor the expected MaxUnavailable.
+	Warning string `json:"warning,omitempty"`
+
+	// err is the error collecting the StatefulSets or the PodDisruptionBudget of the node group.
+	err error
+}
 
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
+type NodeStatefulSetDescription struct {
+	Name    string `json:"name"`
+	Ready   int32  `json:"ready"`
+	Desired int32  `json:"desired"`
+}
 
-		showWorkload(d.client, item.Namespace, selector, w)
+type PodDisruptionBudgetDescription struct {
+	Name               string              `json:"name"`
+	MaxUnavailable     *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	AllowedDisruptions int32               `json:"allowedDisruptions"`
+	CurrentHealthy     int32               `json:"currentHealthy"`
+	DesiredHealthy     int32               `json:"desiredHealthy"`
+}
 
-		secretVolumes := make(map[string]*core.SecretVolumeSource)
-		if item.Spec.DatabaseSecret != nil {
-			secretVolumes["Database"] = item.Spec.DatabaseSecret
-		}
-		showSecret(d.client, item.Namespace, secretVolumes, w)
+func getElasticsearchNodeGroups(cache *objectCache, item *api.Elasticsearch, desc *Description) []NodeGroupDescription {
+	topology := item.Spec.Topology
+	nodes := []struct {
+		role string
+		node api.ElasticsearchNode
+	}{
+		{"master", topology.Master},
+		{"data", topology.Data},
+		{"client", topology.Client},
+	}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
+	groups := make([]NodeGroupDescription, 0, len(nodes))
+	for _, n := range nodes {
+		group := NodeGroupDescription{
+			Role:           n.role,
+			Prefix:         n.node.Prefix,
+			Replicas:       n.node.Replicas,
+			Requests:       n.node.Resources.Requests,
+			Limits:         n.node.Resources.Limits,
+			MaxUnavailable: n.node.MaxUnavailable,
+		}
+		if n.node.Storage != nil {
+			val, _ := n.node.Storage.Resources.Requests[core.ResourceStorage]
+			group.Capacity = val.String()
+		}
+
+		nodeSelector := item.OffshootSelectors()
+		nodeSelector["node.role."+n.role] = "set"
+		selector := labels.SelectorFromSet(nodeSelector)
+		statefulSets, err := cache.statefulSets(item.Namespace, selector)
+		if err != nil {
+			desc.warn(fmt.Sprintf("StatefulSets of %s nodes", n.role), err)
+			group.err = err
+			groups = append(groups, group)
+			continue
 		}
-
-		if snapshots != nil {
-			listSnapshots(snapshots, w)
+		pdbNames := []string{elasticsearchNodeName(item, n.role, n.node)}
+		for _, ss := range statefulSets {
+			group.StatefulSets = append(group.StatefulSets, NodeStatefulSetDescription{
+				Name:    ss.Name,
+				Ready:   ss.Status.ReadyReplicas,
+				Desired: types.Int32(ss.Spec.Replicas),
+			})
+			if ss.Name != pdbNames[0] {
+				pdbNames = append(pdbNames, ss.Name)
+			}
 		}
 
-		if events != nil {
-			DescribeEvents(events, w)
+		maxUnavailable := n.node.MaxUnavailable
+		if maxUnavailable == nil {
+			maxUnavailable = item.Spec.MaxUnavailable
 		}
-
-		return nil
-	})
+		getNodeDisruptionBudget(cache, item.Namespace, pdbNames, maxUnavailable, &group, desc)
+		groups = append(groups, group)
+	}
+	return groups
 }
 
-type ElasticsearchDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha1Interface
+// elasticsearchNodeName returns the offshoot name of a node group, which
+// the operator uses for both its StatefulSet and its PodDisruptionBudget.
+func elasticsearchNodeName(item *api.Elasticsearch, role string, node api.ElasticsearchNode) string {
+	prefix := node.Prefix
+	if prefix == "" {
+		prefix = role
+	}
+	return fmt.Sprintf("%s-%s", prefix, item.OffshootName())
 }
 
-func (d *ElasticsearchDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
-	item, err := d.kubedb.Elasticsearches(namespace).Get(name, metav1.GetOptions{})
-	if err != nil {
-		return "", err
+// getNodeDisruptionBudget looks up the PodDisruptionBudget of a node group under each of names
+// and checks it against the expected maxUnavailable.
+func getNodeDisruptionBudget(cache *objectCache, namespace string, names []string, maxUnavailable *intstr.IntOrString, group *NodeGroupDescription, desc *Description) {
+	var pdb *policy.PodDisruptionBudget
+	for _, name := range names {
+		var err error
+		pdb, err = cache.podDisruptionBudget(namespace, name)
+		if err == nil {
+			break
+		}
+		pdb = nil
+		if !kerr.IsNotFound(err) {
+			desc.warn(fmt.Sprintf("PodDisruptionBudget %s", name), err)
+			group.err = err
+			return
+		}
 	}
 
-	selector := labels.SelectorFromSet(item.OffshootSelectors())
-
-	snapshots, err := d.kubedb.Snapshots(item.Namespace).List(
-		metav1.ListOptions{
-			LabelSelector: selector.String(),
-		},
-	)
-	if err != nil {
-		return "", err
+	if pdb == nil {
+		if maxUnavailable != nil {
+			group.Warning = fmt.Sprintf("no PodDisruptionBudget found for MaxUnavailable %s", maxUnavailable.String())
+		}
+		return
 	}
 
-	var events *core.EventList
-	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
-		if err != nil {
-			return "", err
+	group.PodDisruptionBudget = &PodDisruptionBudgetDescription{
+		Name:               pdb.Name,
+		MaxUnavailable:     pdb.Spec.MaxUnavailable,
+		AllowedDisruptions: pdb.Status.PodDisruptionsAllowed,
+		CurrentHealthy:     pdb.Status.CurrentHealthy,
+		DesiredHealthy:     pdb.Status.DesiredHealthy,
+	}
+	if maxUnavailable != nil {
+		actual := "<none>"
+		if pdb.Spec.MaxUnavailable != nil {
+			actual = pdb.Spec.MaxUnavailable.String()
+		}
+		if actual != maxUnavailable.String() {
+			group.Warning = fmt.Sprintf("MaxUnavailable is %s, expected %s", actual, maxUnavailable.String())
 		}
 	}
-
-	return d.describeElasticsearch(item, selector, snapshots, events)
 }
 
-func (d *ElasticsearchDescriber) describeElasticsearch(item *api.Elasticsearch, selector labels.Selector, snapshots *api.SnapshotList, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := versioned.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-		if len(item.Status.Reason) > 0 {
-			w.Write(LEVEL_0, "Reason:\t%s\n", item.Status.Reason)
-		}
+func describeElasticsearchTopology(groups []NodeGroupDescription, w versioned.PrefixWriter) {
+	if len(groups) == 0 {
+		return
+	}
 
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", types.Int32(item.Spec.Replicas))
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "Node Groups:\n")
+	for _, g := range groups {
+		w.Write(LEVEL_1, "%s:\n", strings.Title(g.Role))
+		if g.Prefix != "" {
+			w.Write(LEVEL_2, "Prefix:\t%s\n", g.Prefix)
 		}
-
-		describeInitialization(item.Spec.Init, w)
-
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
-
-		showWorkload(d.client, item.Namespace, selector, w)
-
-		secretVolumes := make(map[string]*core.SecretVolumeSource)
-		if item.Spec.DatabaseSecret != nil {
-			secretVolumes["Database"] = item.Spec.DatabaseSecret
+		if g.Replicas != nil {
+			w.Write(LEVEL_2, "Replicas:\t%d  total\n", types.Int32(g.Replicas))
 		}
-		if item.Spec.CertificateSecret != nil {
-			secretVolumes["Certificate"] = item.Spec.CertificateSecret
+		if g.Capacity != "" {
+			w.Write(LEVEL_2, "Capacity:\t%s\n", g.Capacity)
 		}
-		showSecret(d.client, item.Namespace, secretVolumes, w)
-
-		specific := map[string]labels.Selector{
-			"master": labels.SelectorFromSet(map[string]string{"node.role.master": "set"}),
-			"client": labels.SelectorFromSet(map[string]string{"node.role.client": "set"}),
-			"data":   labels.SelectorFromSet(map[string]string{"node.role.data": "set"}),
+		if len(g.Requests) > 0 {
+			w.Write(LEVEL_2, "Requests:\t%s\n", resourceListToString(g.Requests))
 		}
-		showTopology(d.client, item.Namespace, selector, specific, w)
-
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
+		if len(g.Limits) > 0 {
+			w.Write(LEVEL_2, "Limits:\t%s\n", resourceListToString(g.Limits))
 		}
-
-		if snapshots != nil {
-			listSnapshots(snapshots, w)
+		if g.MaxUnavailable != nil {
+			w.Write(LEVEL_2, "MaxUnavailable:\t%s\n", g.MaxUnavailable.String())
 		}
-
-		if events != nil {
-			DescribeEvents(events, w)
+		for _, ss := range g.StatefulSets {
+			w.Write(LEVEL_2, "StatefulSet:\t%s\n", ss.Name)
+			w.Write(LEVEL_2, "Ready:\t%d/%d\n", ss.Ready, ss.Desired)
+		}
+		if g.err != nil {
+			continue
 		}
 
-		return nil
-	})
-}
-
-type PostgresDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha1Interface
+		if pdb := g.PodDisruptionBudget; pdb != nil {
+			w.Write(LEVEL_2, "PodDisruptionBudget:\t%s\n", pdb.Name)
+			if pdb.MaxUnavailable != nil {
+				w.Write(LEVEL_3, "MaxUnavailable:\t%s\n", pdb.MaxUnavailable.String())
+			}
+			w.Write(LEVEL_3, "Allowed Disruptions:\t%d\n", pdb.AllowedDisruptions)
+			w.Write(LEVEL_3, "Healthy:\t%d current / %d desired\n", pdb.CurrentHealthy, pdb.DesiredHealthy)
+		} else {
+			w.Write(LEVEL_2, "PodDisruptionBudget:\t<none>\n")
+		}
+		if g.Warning != "" {
+			w.Write(LEVEL_3, "WARNING:\t%s\n", g.Warning)
+		}
+	}
 }
 
-func (d *PostgresDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+func getPostgres(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
 	item, err := d.kubedb.Postgreses(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	selector := labels.SelectorFromSet(item.OffshootSelectors())
-
-	snapshots, err := d.kubedb.Snapshots(item.Namespace).List(
-		metav1.ListOptions{
-			LabelSelector: selector.String(),
+	desc, err := d.getDatabase(d.cache, d.dc, database{
+		obj:         item,
+		meta:        item.ObjectMeta,
+		kind:        api.ResourceKindPostgres,
+		phase:       item.Status.Phase,
+		reason:      item.Status.Reason,
+		spec:        specSummary(string(item.Spec.Version), item.Spec.Replicas, item.Spec.StorageType, item.Spec.Storage, item.Spec.TerminationPolicy),
+		selector:    selector,
+		secrets:     databaseSecrets(item.Spec.DatabaseSecret, nil),
+		monitor:     item.Spec.Monitor,
+		app:         item.AppBindingMeta(),
+		schedule:    item.Spec.BackupSchedule,
+		storage:     item.Spec.Storage,
+		config:      item.Spec.ConfigSource,
+		podTemplate: &item.Spec.PodTemplate,
+		serviceTemplates: []serviceTemplate{
+			{title: "Service Template", service: item.ServiceName(), template: item.Spec.ServiceTemplate},
+			{title: "Replica Service Template", service: item.ReplicasServiceName(), template: item.Spec.ReplicaServiceTemplate},
 		},
-	)
+	}, describerSettings)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	if item.Spec.Archiver != nil {
+		desc.Archiver = item.Spec.Archiver.Storage
+	}
+	desc.Init = item.Spec.Init
 
-	var events *core.EventList
-	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
-		if err != nil {
-			return "", err
-		}
+	specific := map[string]labels.Selector{
+		"primary": labels.SelectorFromSet(map[string]string{"kubedb.com/role": "primary"}),
+		"replica": labels.SelectorFromSet(map[string]string{"kubedb.com/role": "replica"}),
 	}
+	desc.Topology = getTopology(d.cache, item.Namespace, "Topology", selector, specific, desc)
 
-	return d.describePostgres(item, selector, snapshots, events)
+	desc.PostgresHA = getPostgresHA(d.cache, item, selector, desc)
+	return desc, nil
 }
 
-func (d *PostgresDescriber) describePostgres(item *api.Postgres, selector labels.Selector, snapshots *api.SnapshotList, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := versioned.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", types.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-		if len(item.Status.Reason) > 0 {
-			w.Write(LEVEL_0, "Reason:\t%s\n", item.Status.Reason)
-		}
-
-		describeArchiver(item.Spec.Archiver, w)
-
-		describeInitialization(item.Spec.Init, w)
-
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
-
-		showWorkload(d.client, item.Namespace, selector, w)
-
-		secretVolumes := make(map[string]*core.SecretVolumeSource)
-		if item.Spec.DatabaseSecret != nil {
-			secretVolumes["Database"] = item.Spec.DatabaseSecret
-		}
-		showSecret(d.client, item.Namespace, secretVolumes, w)
-
-		specific := map[string]labels.Selector{
-			"primary": labels.SelectorFromSet(map[string]string{"kubedb.com/role": "primary"}),
-			"replica": labels.SelectorFromSet(map[string]string{"kubedb.com/role": "replica"}),
-		}
-		showTopology(d.client, item.Namespace, selector, specific, w)
+// PostgresHADescription holds the high availability settings of a Postgres and the pods currently
+// acting as primary and standby.
+type PostgresHADescription struct {
+	StandbyMode    *api.PostgresStandbyMode   `json:"standbyMode,omitempty"`
+	StreamingMode  *api.PostgresStreamingMode `json:"streamingMode,omitempty"`
+	LeaderElection *api.LeaderElectionConfig  `json:"leaderElection,omitempty"`
+	Primary        string                     `json:"primary,omitempty"`
+	// Standby is nil if the pods could not be listed.
+	Standby []string `json:"standby,omitempty"`
+}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
-		}
+func getPostgresHA(cache *objectCache, item *api.Postgres, selector labels.Selector, desc *Description) *PostgresHADescription {
+	ha := &PostgresHADescription{
+		StandbyMode:    item.Spec.StandbyMode,
+		StreamingMode:  item.Spec.StreamingMode,
+		LeaderElection: item.Spec.LeaderElection,
+	}
 
-		if snapshots != nil {
-			listSnapshots(snapshots, w)
+	pods, err := cache.pods(item.Namespace, selector)
+	if err != nil {
+		desc.warn("Pods", err)
+		return ha
+	}
+	ha.Standby = make([]string, 0)
+	for _, pod := range pods {
+		switch pod.Labels[api.LabelRole] {
+		case "primary":
+			ha.Primary = pod.Name
+		case "replica":
+			ha.Standby = append(ha.Standby, pod.Name)
 		}
+	}
+	return ha
+}
 
-		if events != nil {
-			DescribeEvents(events, w)
-		}
+func describePostgresHA(ha *PostgresHADescription, w versioned.PrefixWriter) {
+	if ha == nil {
+		return
+	}
 
-		return nil
-	})
-}
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "High Availability:\n")
+	if ha.StandbyMode != nil {
+		w.Write(LEVEL_1, "StandbyMode:\t%s\n", *ha.StandbyMode)
+	}
+	if ha.StreamingMode != nil {
+		w.Write(LEVEL_1, "StreamingMode:\t%s\n", *ha.StreamingMode)
+	}
+	if le := ha.LeaderElection; le != nil {
+		w.Write(LEVEL_1, "LeaderElection:\n")
+		w.Write(LEVEL_2, "LeaseDuration:\t%ds\n", le.LeaseDurationSeconds)
+		w.Write(LEVEL_2, "RenewDeadline:\t%ds\n", le.RenewDeadlineSeconds)
+		w.Write(LEVEL_2, "RetryPeriod:\t%ds\n", le.RetryPeriodSeconds)
+	}
 
-type MySQLDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha1Interface
+	if ha.Standby == nil {
+		return
+	}
+	w.Write(LEVEL_1, "Primary:\t%s\n", orNone(ha.Primary))
+	if len(ha.Standby) == 0 {
+		w.Write(LEVEL_1, "Standby:\t<none>\n")
+	} else {
+		w.Write(LEVEL_1, "Standby:\t%s\n", strings.Join(ha.Standby, ", "))
+	}
 }
 
-func (d *MySQLDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+func getMySQL(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
 	item, err := d.kubedb.MySQLs(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	selector := labels.SelectorFromSet(item.OffshootSelectors())
-
-	snapshots, err := d.kubedb.Snapshots(item.Namespace).List(
-		metav1.ListOptions{
-			LabelSelector: selector.String(),
+	desc, err := d.getDatabase(d.cache, d.dc, database{
+		obj:         item,
+		meta:        item.ObjectMeta,
+		kind:        api.ResourceKindMySQL,
+		phase:       item.Status.Phase,
+		reason:      item.Status.Reason,
+		spec:        specSummary(string(item.Spec.Version), item.Spec.Replicas, item.Spec.StorageType, item.Spec.Storage, item.Spec.TerminationPolicy),
+		selector:    selector,
+		secrets:     databaseSecrets(item.Spec.DatabaseSecret, nil),
+		monitor:     item.Spec.Monitor,
+		app:         item.AppBindingMeta(),
+		schedule:    item.Spec.BackupSchedule,
+		storage:     item.Spec.Storage,
+		config:      item.Spec.ConfigSource,
+		podTemplate: &item.Spec.PodTemplate,
+		serviceTemplates: []serviceTemplate{
+			{title: "Service Template", service: item.ServiceName(), template: item.Spec.ServiceTemplate},
 		},
-	)
+	}, describerSettings)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var events *core.EventList
-	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
-		if err != nil {
-			return "", err
-		}
+	if item.Spec.Topology != nil {
+		desc.MySQLCluster = item.Spec.Topology
+		desc.Topology = getTopology(d.cache, item.Namespace, "Topology", selector, replicationRoles, desc)
 	}
-
-	return d.describeMySQL(item, selector, snapshots, events)
+	return desc, nil
 }
 
-func (d *MySQLDescriber) describeMySQL(item *api.MySQL, selector labels.Selector, snapshots *api.SnapshotList, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := versioned.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", types.Int32(item.Spec.Replicas))
+func describeMySQLTopology(topology *api.MySQLClusterTopology, w versioned.PrefixWriter) {
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "Cluster:\n")
+	if topology.Mode != nil {
+		w.Write(LEVEL_1, "Mode:\t%s\n", *topology.Mode)
+	}
+	if group := topology.Group; group != nil {
+		w.Write(LEVEL_1, "Group:\n")
+		w.Write(LEVEL_2, "Name:\t%s\n", group.Name)
+		if group.Mode != nil {
+			w.Write(LEVEL_2, "Mode:\t%s\n", *group.Mode)
 		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-		if len(item.Status.Reason) > 0 {
-			w.Write(LEVEL_0, "Reason:\t%s\n", item.Status.Reason)
+		if group.BaseServerID != nil {
+			w.Write(LEVEL_2, "BaseServerID:\t%d\n", *group.BaseServerID)
 		}
+	}
+}
 
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
+func getMariaDB(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.MariaDBs(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
 
-		showWorkload(d.client, item.Namespace, selector, w)
+	desc, err := d.getDatabase(d.cache, d.dc, database{
+		obj:         item,
+		meta:        item.ObjectMeta,
+		kind:        api.ResourceKindMariaDB,
+		phase:       item.Status.Phase,
+		reason:      item.Status.Reason,
+		spec:        specSummary(string(item.Spec.Version), item.Spec.Replicas, item.Spec.StorageType, item.Spec.Storage, item.Spec.TerminationPolicy),
+		selector:    labels.SelectorFromSet(item.OffshootSelectors()),
+		secrets:     databaseSecrets(item.Spec.DatabaseSecret, nil),
+		monitor:     item.Spec.Monitor,
+		app:         item.AppBindingMeta(),
+		storage:     item.Spec.Storage,
+		config:      item.Spec.ConfigSource,
+		podTemplate: &item.Spec.PodTemplate,
+		serviceTemplates: []serviceTemplate{
+			{title: "Service Template", service: item.ServiceName(), template: item.Spec.ServiceTemplate},
+		},
+	}, describerSettings)
+	if err != nil {
+		return nil, err
+	}
+	desc.Init = item.Spec.Init
+	return desc, nil
+}
 
-		secretVolumes := make(map[string]*core.SecretVolumeSource)
-		if item.Spec.DatabaseSecret != nil {
-			secretVolumes["Database"] = item.Spec.DatabaseSecret
-		}
-		showSecret(d.client, item.Namespace, secretVolumes, w)
+func getPerconaXtraDB(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.PerconaXtraDBs(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
-		}
+	selector := labels.SelectorFromSet(item.OffshootSelectors())
+	desc, err := d.getDatabase(d.cache, d.dc, database{
+		obj:         item,
+		meta:        item.ObjectMeta,
+		kind:        api.ResourceKindPerconaXtraDB,
+		phase:       item.Status.Phase,
+		reason:      item.Status.Reason,
+		spec:        specSummary(string(item.Spec.Version), item.Spec.Replicas, item.Spec.StorageType, item.Spec.Storage, item.Spec.TerminationPolicy),
+		selector:    selector,
+		secrets:     databaseSecrets(item.Spec.DatabaseSecret, nil),
+		monitor:     item.Spec.Monitor,
+		app:         item.AppBindingMeta(),
+		storage:     item.Spec.Storage,
+		config:      item.Spec.ConfigSource,
+		podTemplate: &item.Spec.PodTemplate,
+		serviceTemplates: []serviceTemplate{
+			{title: "Service Template", service: item.ServiceName(), template: item.Spec.ServiceTemplate},
+		},
+	}, describerSettings)
+	if err != nil {
+		return nil, err
+	}
+	desc.Init = item.Spec.Init
 
-		if snapshots != nil {
-			listSnapshots(snapshots, w)
+	if pxc := item.Spec.PXC; pxc != nil {
+		desc.PXC = &PXCDescription{
+			ClusterName:      pxc.ClusterName,
+			ProxysqlReplicas: pxc.Proxysql.Replicas,
 		}
-
-		if events != nil {
-			DescribeEvents(events, w)
+		specific := map[string]labels.Selector{
+			"xtradb":   labels.SelectorFromSet(item.XtraDBSelectors()),
+			"proxysql": labels.SelectorFromSet(item.ProxysqlSelectors()),
 		}
-
-		return nil
-	})
-}
-
-type MongoDBDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha1Interface
+		desc.Topology = getTopology(d.cache, item.Namespace, "Topology", selector, specific, desc)
+	}
+	return desc, nil
 }
 
-func (d *MongoDBDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+func getMongoDB(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
 	item, err := d.kubedb.MongoDBs(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	selector := labels.SelectorFromSet(item.OffshootSelectors())
-
-	snapshots, err := d.kubedb.Snapshots(item.Namespace).List(
-		metav1.ListOptions{
-			LabelSelector: selector.String(),
+	desc, err := d.getDatabase(d.cache, d.dc, database{
+		obj:         item,
+		meta:        item.ObjectMeta,
+		kind:        api.ResourceKindMongoDB,
+		phase:       item.Status.Phase,
+		reason:      item.Status.Reason,
+		spec:        specSummary(string(item.Spec.Version), item.Spec.Replicas, item.Spec.StorageType, item.Spec.Storage, item.Spec.TerminationPolicy),
+		selector:    selector,
+		secrets:     databaseSecrets(item.Spec.DatabaseSecret, nil),
+		monitor:     item.Spec.Monitor,
+		app:         item.AppBindingMeta(),
+		schedule:    item.Spec.BackupSchedule,
+		storage:     item.Spec.Storage,
+		config:      item.Spec.ConfigSource,
+		podTemplate: item.Spec.PodTemplate,
+		serviceTemplates: []serviceTemplate{
+			{title: "Service Template", service: item.ServiceName(), template: item.Spec.ServiceTemplate},
 		},
-	)
+		tls:   mongoDBTLS(item),
+		certs: mongoDBCertificateSecrets(item),
+	}, describerSettings)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var events *core.EventList
-	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
-		if err != nil {
-			return "", err
-		}
+	if item.Spec.ReplicaSet != nil {
+		desc.ReplicaSet = item.Spec.ReplicaSet.Name
+		desc.Topology = getTopology(d.cache, item.Namespace, "Topology", selector, replicationRoles, desc)
 	}
 
-	return d.describeMongoDB(item, selector, snapshots, events)
+	if item.Spec.ShardTopology != nil {
+		desc.ShardTopology = getMongoDBShardTopology(d.cache, item, desc)
+	}
+	return desc, nil
 }
 
-func (d *MongoDBDescriber) describeMongoDB(item *api.MongoDB, selector labels.Selector, snapshots *api.SnapshotList, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := versioned.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
+// replicationRoles selects the members of a replicated database by the role
+// they currently hold.
+var replicationRoles = map[string]labels.Selector{
+	"primary":   labels.SelectorFromSet(map[string]string{api.LabelRole: "primary"}),
+	"secondary": labels.SelectorFromSet(map[string]string{api.LabelRole: "secondary"}),
+}
 
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", types.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-		if len(item.Status.Reason) > 0 {
-			w.Write(LEVEL_0, "Reason:\t%s\n", item.Status.Reason)
-		}
+// MongoDBShardTopologyDescription describes the shards, config server and mongos of a sharded MongoDB.
+type MongoDBShardTopologyDescription struct {
+	Shards       []MongoDBNodeDescription `json:"shards"`
+	ConfigServer MongoDBNodeDescription   `json:"configServer"`
+	Mongos       MongoDBNodeDescription   `json:"mongos"`
+}
 
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
+type MongoDBNodeDescription struct {
+	Name         string                   `json:"name"`
+	ReplicaSet   string                   `json:"replicaSet,omitempty"`
+	Replicas     int32                    `json:"replicas"`
+	Prefix       string                   `json:"prefix,omitempty"`
+	Storage      *StorageSummary          `json:"storage,omitempty"`
+	ConfigSource *ConfigSourceDescription `json:"configSource,omitempty"`
+	// Pods is nil if the pods of the node could not be listed.
+	Pods     *PodStatusDescription `json:"pods,omitempty"`
+	Strategy string                `json:"strategy,omitempty"`
+	Topology *TopologyDescription  `json:"topology,omitempty"`
+}
 
-		showWorkload(d.client, item.Namespace, selector, w)
+func getMongoDBShardTopology(cache *objectCache, item *api.MongoDB, desc *Description) *MongoDBShardTopologyDescription {
+	topology := item.Spec.ShardTopology
+	shards := &MongoDBShardTopologyDescription{}
 
-		secretVolumes := make(map[string]*core.SecretVolumeSource)
-		if item.Spec.DatabaseSecret != nil {
-			secretVolumes["Database"] = item.Spec.DatabaseSecret
-		}
-		showSecret(d.client, item.Namespace, secretVolumes, w)
+	for i := int32(0); i < topology.Shard.Shards; i++ {
+		selector := labels.SelectorFromSet(item.ShardSelectors(i))
+		shard := getMongoDBNode(cache, item.Namespace, item.ShardNodeName(i), topology.Shard.MongoDBNode, topology.Shard.Storage, selector, desc)
+		shard.ReplicaSet = item.ShardRepSetName(i)
+		shard.Topology = getTopology(cache, item.Namespace, fmt.Sprintf("Shard %d Topology", i), selector, replicationRoles, desc)
+		shards.Shards = append(shards.Shards, shard)
+	}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
-		}
+	selector := labels.SelectorFromSet(item.ConfigSvrSelectors())
+	shards.ConfigServer = getMongoDBNode(cache, item.Namespace, item.ConfigSvrNodeName(), topology.ConfigServer.MongoDBNode, topology.ConfigServer.Storage, selector, desc)
+	shards.ConfigServer.ReplicaSet = item.ConfigSvrRepSetName()
+	shards.ConfigServer.Topology = getTopology(cache, item.Namespace, "Config Server Topology", selector, replicationRoles, desc)
 
-		if snapshots != nil {
-			listSnapshots(snapshots, w)
-		}
+	selector = labels.SelectorFromSet(item.MongosSelectors())
+	shards.Mongos = getMongoDBNode(cache, item.Namespace, item.MongosNodeName(), topology.Mongos.MongoDBNode, nil, selector, desc)
+	shards.Mongos.Strategy = string(topology.Mongos.Strategy.Type)
+	return shards
+}
 
-		if events != nil {
-			DescribeEvents(events, w)
-		}
+func getMongoDBNode(cache *objectCache, namespace, name string, node api.MongoDBNode, pvcSpec *core.PersistentVolumeClaimSpec, selector labels.Selector, desc *Description) MongoDBNodeDescription {
+	nd := MongoDBNodeDescription{
+		Name:     name,
+		Replicas: node.Replicas,
+		Prefix:   node.Prefix,
+		Storage:  summarizeStorage(pvcSpec),
+	}
+	nd.ConfigSource = getConfigSource(cache, namespace, node.ConfigSource, false)
+	if cfg := nd.ConfigSource; cfg != nil && cfg.err != nil {
+		desc.warn(fmt.Sprintf("config source %s %s of %s", cfg.Kind, cfg.Name, name), cfg.err)
+	}
+	pods, err := getPodStatus(cache, namespace, selector)
+	if err != nil {
+		desc.warn(fmt.Sprintf("Pods of %s", name), err)
+	}
+	nd.Pods = pods
+	return nd
+}
 
-		return nil
-	})
+func describeMongoDBShardTopology(topology *MongoDBShardTopologyDescription, w versioned.PrefixWriter) {
+	if topology == nil {
+		return
+	}
+
+	for i := range topology.Shards {
+		w.Write(LEVEL_0, "\n")
+		w.Write(LEVEL_0, "Shard %d:\n", i)
+		describeMongoDBNode(LEVEL_0, &topology.Shards[i], w)
+	}
+
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "Config Server:\n")
+	describeMongoDBNode(LEVEL_0, &topology.ConfigServer, w)
+
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "Mongos:\n")
+	describeMongoDBNode(LEVEL_0, &topology.Mongos, w)
 }
 
-type RedisDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha1Interface
+// describeMongoDBNode writes node inside the section at level.
+func describeMongoDBNode(level int, node *MongoDBNodeDescription, w versioned.PrefixWriter) {
+	w.Write(level+1, "Name:\t%s\n", node.Name)
+	if node.ReplicaSet != "" {
+		w.Write(level+1, "ReplicaSet:\t%s\n", node.ReplicaSet)
+	}
+	w.Write(level+1, "Replicas:\t%d  total\n", node.Replicas)
+	if node.Prefix != "" {
+		w.Write(level+1, "Prefix:\t%s\n", node.Prefix)
+	}
+	if st := node.Storage; st != nil {
+		w.Write(level+1, "Storage:\n")
+		if st.StorageClass != "" {
+			w.Write(level+2, "StorageClass:\t%s\n", st.StorageClass)
+		}
+		w.Write(level+2, "Capacity:\t%s\n", st.Capacity)
+		if st.AccessModes != "" {
+			w.Write(level+2, "Access Modes:\t%s\n", st.AccessModes)
+		}
+	}
+	if config := node.ConfigSource; config != nil {
+		w.Write(level+1, "ConfigSource:\t%s %s\n", config.Kind, config.Name)
+		if config.Warning != "" {
+			w.Write(level+1, "WARNING:\t%s\n", config.Warning)
+		}
+	}
+	if p := node.Pods; p != nil {
+		w.Write(level+1, "Pods Status:\t%d Running / %d Waiting / %d Succeeded / %d Failed\n", p.Running, p.Waiting, p.Succeeded, p.Failed)
+	}
+	if node.Strategy != "" {
+		w.Write(level+1, "Strategy:\t%s\n", node.Strategy)
+	}
+	describeTopology(level+1, node.Topology, w)
 }
 
-func (d *RedisDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+func getRedis(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
 	item, err := d.kubedb.Redises(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	selector := labels.SelectorFromSet(item.OffshootSelectors())
-
-	snapshots, err := d.kubedb.Snapshots(item.Namespace).List(
-		metav1.ListOptions{
-			LabelSelector: selector.String(),
+	desc, err := d.getDatabase(d.cache, d.dc, database{
+		obj:         item,
+		meta:        item.ObjectMeta,
+		kind:        api.ResourceKindRedis,
+		phase:       item.Status.Phase,
+		reason:      item.Status.Reason,
+		spec:        specSummary(string(item.Spec.Version), item.Spec.Replicas, item.Spec.StorageType, item.Spec.Storage, item.Spec.TerminationPolicy),
+		selector:    labels.SelectorFromSet(item.OffshootSelectors()),
+		monitor:     item.Spec.Monitor,
+		app:         item.AppBindingMeta(),
+		storage:     item.Spec.Storage,
+		config:      item.Spec.ConfigSource,
+		podTemplate: &item.Spec.PodTemplate,
+		serviceTemplates: []serviceTemplate{
+			{title: "Service Template", service: item.ServiceName(), template: item.Spec.ServiceTemplate},
 		},
-	)
+	}, describerSettings)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var events *core.EventList
-	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
-		if err != nil {
-			return "", err
-		}
+	if item.Spec.Mode == api.RedisModeCluster && item.Spec.Cluster != nil {
+		desc.RedisCluster = getRedisCluster(d.cache, item, desc)
 	}
-
-	return d.describeRedis(item, selector, snapshots, events)
+	return desc, nil
 }
 
-func (d *RedisDescriber) describeRedis(item *api.Redis, selector labels.Selector, snapshots *api.SnapshotList, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := versioned.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", types.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-		if len(item.Status.Reason) > 0 {
-			w.Write(LEVEL_0, "Reason:\t%s\n", item.Status.Reason)
-		}
-
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
+// redisRoles selects the cluster nodes by the role they currently hold.
+var redisRoles = map[string]labels.Selector{
+	"master":  labels.SelectorFromSet(map[string]string{api.LabelRole: "master"}),
+	"replica": labels.SelectorFromSet(map[string]string{api.LabelRole: "replica"}),
+}
 
-		showWorkload(d.client, item.Namespace, selector, w)
+// RedisClusterDescription describes the shards of a Redis running in cluster mode.
+type RedisClusterDescription struct {
+	Master   int32                   `json:"master"`
+	Replicas int32                   `json:"replicas"`
+	Shards   []RedisShardDescription `json:"shards"`
+}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
-		}
+type RedisShardDescription struct {
+	StatefulSet string `json:"statefulSet"`
+	Ready       int32  `json:"ready"`
+	Desired     int32  `json:"desired"`
+	// Degraded is set if fewer than the desired members of the shard are ready.
+	Degraded bool `json:"degraded,omitempty"`
+	// Status is set if the StatefulSet of the shard could not be read.
+	Status string `json:"status,omitempty"`
+	// Pods is nil if the pods of the shard could not be listed.
+	Pods []TopologyPodDescription `json:"pods,omitempty"`
+}
 
-		if snapshots != nil {
-			listSnapshots(snapshots, w)
-		}
+func getRedisCluster(cache *objectCache, item *api.Redis, desc *Description) *RedisClusterDescription {
+	cluster := &RedisClusterDescription{
+		Master:   types.Int32(item.Spec.Cluster.Master),
+		Replicas: types.Int32(item.Spec.Cluster.Replicas),
+	}
 
-		if events != nil {
-			DescribeEvents(events, w)
+	for i := 0; i < int(cluster.Master); i++ {
+		shard := RedisShardDescription{
+			StatefulSet: item.StatefulSetNameWithShard(i),
+			// every shard runs one master and the configured number of replicas
+			Desired: cluster.Replicas + 1,
 		}
-
-		return nil
-	})
-}
-
-type MemcachedDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha1Interface
+		cluster.Shards = append(cluster.Shards, getRedisShard(cache, item.Namespace, shard, desc))
+	}
+	return cluster
 }
 
-func (d *MemcachedDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
-	item, err := d.kubedb.Memcacheds(namespace).Get(name, metav1.GetOptions{})
+func getRedisShard(cache *objectCache, namespace string, shard RedisShardDescription, desc *Description) RedisShardDescription {
+	name := shard.StatefulSet
+	ss, err := cache.statefulSet(namespace, name)
 	if err != nil {
-		return "", err
+		desc.warn(fmt.Sprintf("StatefulSet %s", name), err)
+		shard.Status = fmt.Sprintf("<unknown>: %v", err)
+		return shard
 	}
+	shard.Ready = ss.Status.ReadyReplicas
+	shard.Degraded = shard.Ready < shard.Desired
 
-	selector := labels.SelectorFromSet(item.OffshootSelectors())
-
-	snapshots, err := d.kubedb.Snapshots(item.Namespace).List(
-		metav1.ListOptions{
-			LabelSelector: selector.String(),
-		},
-	)
+	selector, err := metav1.LabelSelectorAsSelector(ss.Spec.Selector)
 	if err != nil {
-		return "", err
+		desc.warn(fmt.Sprintf("selector of StatefulSet %s", name), err)
+		return shard
 	}
-
-	var events *core.EventList
-	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
-		if err != nil {
-			return "", err
-		}
+	pods, err := cache.pods(namespace, selector)
+	if err != nil {
+		desc.warn(fmt.Sprintf("Pods of StatefulSet %s", name), err)
+		return shard
 	}
-
-	return d.describeMemcached(item, selector, snapshots, events)
+	shard.Pods = topologyPods(pods, redisRoles)
+	return shard
 }
 
-func (d *MemcachedDescriber) describeMemcached(item *api.Memcached, selector labels.Selector, snapshots *api.SnapshotList, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := versioned.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", types.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-		if len(item.Status.Reason) > 0 {
-			w.Write(LEVEL_0, "Reason:\t%s\n", item.Status.Reason)
-		}
-
-		showWorkload(d.client, item.Namespace, selector, w)
+func describeRedisCluster(cluster *RedisClusterDescription, w versioned.PrefixWriter) {
+	if cluster == nil {
+		return
+	}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "Cluster:\n")
+	w.Write(LEVEL_1, "Master:\t%d\n", cluster.Master)
+	w.Write(LEVEL_1, "Replicas:\t%d\n", cluster.Replicas)
+
+	for i, shard := range cluster.Shards {
+		w.Write(LEVEL_0, "\n")
+		w.Write(LEVEL_1, "Shard %d:\n", i)
+		w.Write(LEVEL_2, "StatefulSet:\t%s\n", shard.StatefulSet)
+		if shard.Status != "" {
+			w.Write(LEVEL_2, "Status:\t%s\n", shard.Status)
+			continue
 		}
-
-		if snapshots != nil {
-			listSnapshots(snapshots, w)
+		if shard.Degraded {
+			w.Write(LEVEL_2, "Ready:\t%d/%d (degraded)\n", shard.Ready, shard.Desired)
+		} else {
+			w.Write(LEVEL_2, "Ready:\t%d/%d\n", shard.Ready, shard.Desired)
 		}
-
-		if events != nil {
-			DescribeEvents(events, w)
+		if shard.Pods == nil {
+			continue
 		}
-
-		return nil
-	})
+		w.Write(LEVEL_2, "Role\tPod\tStartTime\tPhase\n")
+		w.Write(LEVEL_2, "----\t---\t---------\t-----\n")
+		describeTopologyPods(LEVEL_2, shard.Pods, w)
+	}
+	w.Flush()
 }
 
-type SnapshotDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha1Interface
+func getMemcached(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.Memcacheds(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Memcached is not backed by volumes, so its spec has no storage.
+	return d.getDatabase(d.cache, d.dc, database{
+		obj:    item,
+		meta:   item.ObjectMeta,
+		kind:   api.ResourceKindMemcached,
+		phase:  item.Status.Phase,
+		reason: item.Status.Reason,
+		spec: SpecSummary{
+			Version:           string(item.Spec.Version),
+			Replicas:          item.Spec.Replicas,
+			TerminationPolicy: item.Spec.TerminationPolicy,
+		},
+		selector:    labels.SelectorFromSet(item.OffshootSelectors()),
+		monitor:     item.Spec.Monitor,
+		app:         item.AppBindingMeta(),
+		config:      item.Spec.ConfigSource,
+		podTemplate: &item.Spec.PodTemplate,
+		serviceTemplates: []serviceTemplate{
+			{title: "Service Template", service: item.ServiceName(), template: item.Spec.ServiceTemplate},
+		},
+	}, describerSettings)
 }
 
-func (d *SnapshotDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+func getSnapshot(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
 	item, err := d.kubedb.Snapshots(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var events *core.EventList
-	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
-		if err != nil {
-			return "", err
-		}
+	desc := &Description{
+		Kind:                api.ResourceKindSnapshot,
+		Name:                item.Name,
+		Namespace:           item.Namespace,
+		CreationTimestamp:   item.CreationTimestamp,
+		CompletionTimestamp: item.Status.CompletionTime,
+		Labels:              item.Labels,
+		Annotations:         item.Annotations,
+		Status:              string(item.Status.Phase),
+		Reason:              item.Status.Reason,
+		Backend:             &item.Spec.Backend,
 	}
 
-	return d.describeSnapshot(item, events)
-}
-
-func (d *SnapshotDescriber) describeSnapshot(item *api.Snapshot, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := versioned.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		if item.Status.CompletionTime != nil {
-			w.Write(LEVEL_0, "CompletionTimestamp:\t%s\n", timeToString(item.Status.CompletionTime))
+	if item.Spec.StorageSecretName != "" {
+		secretVolumes := map[string]*core.SecretVolumeSource{
+			"Storage": {SecretName: item.Spec.StorageSecretName},
 		}
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
+		desc.Secrets = getSecrets(d.cache, item.Namespace, secretVolumes, desc)
+	}
 
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-		if len(item.Status.Reason) > 0 {
-			w.Write(LEVEL_0, "Reason:\t%s\n", item.Status.Reason)
+	if describerSettings.ShowEvents {
+		uids := map[ktypes.UID]bool{item.UID: true}
+		var err error
+		if d.deepEvents {
+			err = snapshotUIDs(d.cache, item, uids)
+		}
+		var events *core.EventList
+		if err == nil {
+			events, err = listEvents(d.cache, item.Namespace, uids, d.eventsType)
 		}
+		if err == nil {
+			desc.Events = getEvents(events, d.deepEvents)
+		} else {
+			desc.warn("Events", err)
+		}
+	}
+	return desc, nil
+}
 
-		w.Write(LEVEL_0, "Storage:\n")
-		describeSnapshotStorage(item.Spec.Backend, w)
+func (o *describerOptions) describeSnapshot(desc *Description, w versioned.PrefixWriter) {
+	w.Write(LEVEL_0, "Name:\t%s\n", desc.Name)
+	w.Write(LEVEL_0, "Namespace:\t%s\n", desc.Namespace)
+	w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&desc.CreationTimestamp))
+	if desc.CompletionTimestamp != nil {
+		w.Write(LEVEL_0, "CompletionTimestamp:\t%s\n", timeToString(desc.CompletionTimestamp))
+	}
+	printLabelsMultiline(LEVEL_0, w, "Labels", desc.Labels)
+	printAnnotationsMultiline(LEVEL_0, w, "Annotations", desc.Annotations)
 
-		secretVolumes := make(map[string]*core.SecretVolumeSource)
-		if item.Spec.StorageSecretName != "" {
-			secretVolumes["Database"] = &core.SecretVolumeSource{SecretName: item.Spec.StorageSecretName}
-		}
-		showSecret(d.client, item.Namespace, secretVolumes, w)
+	w.Write(LEVEL_0, "Status:\t%s\n", desc.Status)
+	if len(desc.Reason) > 0 {
+		w.Write(LEVEL_0, "Reason:\t%s\n", desc.Reason)
+	}
 
-		if events != nil {
-			DescribeEvents(events, w)
-		}
+	w.Write(LEVEL_0, "Storage:\n")
+	describeSnapshotStorage(*desc.Backend, w)
 
-		return nil
-	})
-}
+	describeSecrets(desc.Secrets, w)
 
-type DormantDatabaseDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha1Interface
+	if desc.Events != nil {
+		o.describeEvents(desc.Events, w)
+	}
 }
 
-func (d *DormantDatabaseDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+func getDormantDatabase(d *kubedbDescriber, namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
 	item, err := d.kubedb.DormantDatabases(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	selector := labels.SelectorFromSet(item.OffshootSelectors())
-
-	snapshots, err := d.kubedb.Snapshots(item.Namespace).List(
-		metav1.ListOptions{
-			LabelSelector: selector.String(),
+	origin := item.Spec.Origin
+	desc := &Description{
+		Kind:              api.ResourceKindDormantDatabase,
+		Name:              item.Name,
+		Namespace:         item.Namespace,
+		CreationTimestamp: item.CreationTimestamp,
+		PausedTimestamp:   item.Status.PausingTime,
+		WipeOutTimestamp:  item.Status.WipeOutTime,
+		Labels:            item.Labels,
+		Annotations:       item.Annotations,
+		Status:            string(item.Status.Phase),
+		Reason:            item.Status.Reason,
+		Origin: &OriginDescription{
+			Name:        origin.Name,
+			Namespace:   origin.Namespace,
+			Labels:      origin.Labels,
+			Annotations: origin.Annotations,
 		},
-	)
-	if err != nil {
-		return "", err
 	}
 
-	var events *core.EventList
-	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
-		if err != nil {
-			return "", err
+	selector := labels.SelectorFromSet(item.OffshootSelectors())
+	// the snapshots of a wiped out database are deleted along with its data
+	if item.Status.Phase != api.DormantDatabasePhaseWipedOut {
+		if snapshots, err := d.cache.snapshots(item.Namespace, selector); err == nil {
+			desc.Snapshots = getSnapshots(snapshots)
+		} else {
+			desc.warn("Snapshots", err)
 		}
 	}
 
-	return d.describeDormantDatabase(item, snapshots, events)
-}
-
-func (d *DormantDatabaseDescriber) describeDormantDatabase(item *api.DormantDatabase, snapshots *api.SnapshotList, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := versioned.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		if item.Status.PausingTime != nil {
-			w.Write(LEVEL_0, "PausedTimestamp:\t%s\n", timeToString(item.Status.PausingTime))
-		}
-		if item.Status.WipeOutTime != nil {
-			w.Write(LEVEL_0, "WipeOutTimestamp:\t%s\n", timeToString(item.Status.WipeOutTime))
+	if describerSettings.ShowEvents {
+		if events, err := searchEvents(d.cache, item, selector, d.deepEvents, d.eventsType); err == nil {
+			desc.Events = getEvents(events, d.deepEvents)
+		} else {
+			desc.warn("Events", err)
 		}
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
+	}
+	return desc, nil
+}
 
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-		if len(item.Status.Reason) > 0 {
-			w.Write(LEVEL_0, "Reason:\t%s\n", item.Status.Reason)
-		}
+func (o *describerOptions) describeDormantDatabase(desc *Description, w versioned.PrefixWriter) {
+	w.Write(LEVEL_0, "Name:\t%s\n", desc.Name)
+	w.Write(LEVEL_0, "Namespace:\t%s\n", desc.Namespace)
+	w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&desc.CreationTimestamp))
+	if desc.PausedTimestamp != nil {
+		w.Write(LEVEL_0, "PausedTimestamp:\t%s\n", timeToString(desc.PausedTimestamp))
+	}
+	if desc.WipeOutTimestamp != nil {
+		w.Write(LEVEL_0, "WipeOutTimestamp:\t%s\n", timeToString(desc.WipeOutTimestamp))
+	}
+	printLabelsMultiline(LEVEL_0, w, "Labels", desc.Labels)
+	printAnnotationsMultiline(LEVEL_0, w, "Annotations", desc.Annotations)
 
-		describeOrigin(item.Spec.Origin, w)
+	w.Write(LEVEL_0, "Status:\t%s\n", desc.Status)
+	if len(desc.Reason) > 0 {
+		w.Write(LEVEL_0, "Reason:\t%s\n", desc.Reason)
+	}
 
-		if item.Status.Phase != api.DormantDatabasePhaseWipedOut && snapshots != nil {
-			listSnapshots(snapshots, w)
-		}
+	describeOrigin(desc.Origin, w)
 
-		if events != nil {
-			DescribeEvents(events, w)
-		}
+	if desc.Snapshots != nil {
+		listSnapshots(desc.Snapshots, w)
+	}
 
-		return nil
-	})
+	if desc.Events != nil {
+		o.describeEvents(desc.Events, w)
+	}
 }
 
-func describeStorage(st api.StorageType, pvcSpec *core.PersistentVolumeClaimSpec, w versioned.PrefixWriter) {
+func describeStorage(st api.StorageType, storage *StorageSummary, w versioned.PrefixWriter) {
 	if st == api.StorageTypeEphemeral {
 		w.Write(LEVEL_0, "  StorageType:\t%s\n", api.StorageTypeEphemeral)
 	} else {
 		w.Write(LEVEL_0, "  StorageType:\t%s\n", api.StorageTypeDurable)
 	}
-	if pvcSpec == nil {
+	if storage == nil {
 		w.Write(LEVEL_0, "No volumes.\n")
 		return
 	}
 
-	accessModes := getAccessModesAsString(pvcSpec.AccessModes)
-	val, _ := pvcSpec.Resources.Requests[core.ResourceStorage]
-	capacity := val.String()
 	w.Write(LEVEL_0, "Volume:\n")
-	if pvcSpec.StorageClassName != nil {
-		w.Write(LEVEL_0, "  StorageClass:\t%s\n", *pvcSpec.StorageClassName)
+	if storage.StorageClass != "" {
+		w.Write(LEVEL_0, "  StorageClass:\t%s\n", storage.StorageClass)
 	}
-	w.Write(LEVEL_0, "  Capacity:\t%s\n", capacity)
-	if accessModes != "" {
-		w.Write(LEVEL_0, "  Access Modes:\t%s\n", accessModes)
+	w.Write(LEVEL_0, "  Capacity:\t%s\n", storage.Capacity)
+	if storage.AccessModes != "" {
+		w.Write(LEVEL_0, "  Access Modes:\t%s\n", storage.AccessModes)
 	}
 }
 
-func describeArchiver(archiver *api.PostgresArchiverSpec, w versioned.PrefixWriter) {
-	if archiver == nil {
+func describePXC(pxc *PXCDescription, w versioned.PrefixWriter) {
+	if pxc == nil {
 		return
 	}
-	w.WriteLine("Archiver:")
-	if archiver.Storage != nil {
-		describeSnapshotStorage(*archiver.Storage, w)
+	w.WriteLine("PXC:")
+	w.Write(LEVEL_0, "  ClusterName:\t%s\n", pxc.ClusterName)
+	if pxc.ProxysqlReplicas != nil {
+		w.Write(LEVEL_0, "  Proxysql Replicas:\t%d  total\n", types.Int32(pxc.ProxysqlReplicas))
+	}
+}
+
+func describeArchiver(storage *store.Backend, w versioned.PrefixWriter) {
+	if storage == nil {
+		return
 	}
+	w.WriteLine("Archiver:")
+	describeSnapshotStorage(*storage, w)
 }
 
 func describeInitialization(init *api.InitSpec, w versioned.PrefixWriter) {
@@ -789,10 +1149,10 @@ func describeMonitor(monitor *mona.AgentSpec, w versioned.PrefixWriter) {
 	}
 }
 
-func listSnapshots(snapshotList *api.SnapshotList, w versioned.PrefixWriter) {
+func listSnapshots(snapshots []SnapshotDescription, w versioned.PrefixWriter) {
 	w.Write(LEVEL_0, "\n")
 
-	if len(snapshotList.Items) == 0 {
+	if len(snapshots) == 0 {
 		w.Write(LEVEL_0, "No Snapshots.\n")
 		return
 	}
@@ -801,23 +1161,19 @@ func listSnapshots(snapshotList *api.SnapshotList, w versioned.PrefixWriter) {
 
 	w.Write(LEVEL_0, "  Name\tBucket\tStartTime\tCompletionTime\tPhase\n")
 	w.Write(LEVEL_0, "  ----\t------\t---------\t--------------\t-----\n")
-	for _, e := range snapshotList.Items {
-		location, err := e.Spec.Backend.Location()
-		if err != nil {
-			location = "<invalid>"
-		}
+	for _, e := range snapshots {
 		w.Write(LEVEL_0, "  %s\t%s\t%s\t%s\t%s\n",
 			e.Name,
-			location,
-			timeToString(e.Status.StartTime),
-			timeToString(e.Status.CompletionTime),
-			e.Status.Phase,
+			e.Location,
+			timeToString(e.StartTime),
+			timeToString(e.CompletionTime),
+			e.Phase,
 		)
 	}
 	w.Flush()
 }
 
-func describeOrigin(origin api.Origin, w versioned.PrefixWriter) {
+func describeOrigin(origin *OriginDescription, w versioned.PrefixWriter) {
 	w.Write(LEVEL_0, "\n")
 	w.Write(LEVEL_0, "Origin:\n")
 	w.Write(LEVEL_0, "  Name:\t%s\n", origin.Name)
@@ -826,90 +1182,6 @@ func describeOrigin(origin api.Origin, w versioned.PrefixWriter) {
 	printAnnotationsMultiline(LEVEL_0, w, "Annotations", origin.Annotations)
 }
 
-func showWorkload(client kubernetes.Interface, namespace string, selector labels.Selector, w versioned.PrefixWriter) {
-	pc := client.CoreV1().Pods(namespace)
-	opts := metav1.ListOptions{LabelSelector: selector.String()}
-
-	if statefulSets, err := client.AppsV1().StatefulSets(namespace).List(opts); err == nil {
-		for _, s := range statefulSets.Items {
-			selector, err := metav1.LabelSelectorAsSelector(s.Spec.Selector)
-			if err != nil {
-				continue
-			}
-
-			running, waiting, succeeded, failed, err := getPodStatusForController(pc, selector)
-			if err != nil {
-				continue
-			}
-
-			describeStatefulSet(&s, running, waiting, succeeded, failed, w)
-		}
-	}
-
-	if deployments, err := client.AppsV1().Deployments(namespace).List(opts); err == nil {
-		for _, d := range deployments.Items {
-			selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
-			if err != nil {
-				continue
-			}
-
-			running, waiting, succeeded, failed, err := getPodStatusForController(pc, selector)
-			if err != nil {
-				continue
-			}
-
-			describeDeployment(&d, running, waiting, succeeded, failed, w)
-		}
-	}
-
-	if services, err := client.CoreV1().Services(namespace).List(opts); err == nil {
-		for _, s := range services.Items {
-			endpoints, _ := client.CoreV1().Endpoints(namespace).Get(s.Name, metav1.GetOptions{})
-			describeService(&s, endpoints, w)
-		}
-	}
-}
-
-func showSecret(client kubernetes.Interface, namespace string, secretVolumes map[string]*core.SecretVolumeSource, w versioned.PrefixWriter) {
-	sc := client.CoreV1().Secrets(namespace)
-
-	for key, sv := range secretVolumes {
-		secret, err := sc.Get(sv.SecretName, metav1.GetOptions{})
-		if err != nil {
-			continue
-		}
-		describeSecret(secret, key, w)
-	}
-}
-
-func showTopology(client kubernetes.Interface, namespace string, selector labels.Selector, specific map[string]labels.Selector, w versioned.PrefixWriter) {
-	w.Write(LEVEL_0, "\n")
-	w.Write(LEVEL_0, "Topology:\n")
-	w.Write(LEVEL_0, "  Type\tPod\tStartTime\tPhase\n")
-	w.Write(LEVEL_0, "  ----\t---\t---------\t-----\n")
-
-	pods, _ := client.CoreV1().Pods(namespace).List(metav1.ListOptions{
-		LabelSelector: selector.String(),
-	})
-
-	for _, pod := range pods.Items {
-		types := make([]string, 0)
-		for key, val := range specific {
-			if val.Matches(labels.Set(pod.Labels)) {
-				types = append(types, key)
-			}
-		}
-		w.Write(LEVEL_0, "  %s\t%s\t%s\t%s\n",
-			strings.Join(types, "|"),
-			pod.Name,
-			pod.Status.StartTime,
-			pod.Status.Phase,
-		)
-	}
-
-	w.Flush()
-}
-
 func getAccessModesAsString(modes []core.PersistentVolumeAccessMode) string {
 	modes = removeDuplicateAccessModes(modes)
 	modesStr := []string{}
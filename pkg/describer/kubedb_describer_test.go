@@ -0,0 +1,460 @@
+package describer
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubectl/describe"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	store "kmodules.xyz/objectstore-api/api/v1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+	"kubedb.dev/cli/pkg/internal/fakeapi"
+)
+
+const testNamespace = "demo"
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func testPostgres() *api.Postgres {
+	return &api.Postgres{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: testNamespace, UID: "pg-uid"},
+		Spec: api.PostgresSpec{
+			Version:        "10.2-v2",
+			Replicas:       int32Ptr(2),
+			DatabaseSecret: &core.SecretVolumeSource{SecretName: "pg-auth"},
+			StorageType:    api.StorageTypeDurable,
+			Storage: &core.PersistentVolumeClaimSpec{
+				AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{core.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+			TerminationPolicy: api.TerminationPolicyPause,
+		},
+		Status: api.PostgresStatus{Phase: api.DatabasePhaseRunning},
+	}
+}
+
+// newPostgresServer serves a running Postgres with its catalog version, workload, pods, service,
+// secret, snapshot and events, along with the pod of another database in the same namespace.
+func newPostgresServer(t *testing.T) *fakeapi.Server {
+	pg := testPostgres()
+	selector := pg.OffshootSelectors()
+	podLabels := func(role string) map[string]string {
+		l := pg.OffshootSelectors()
+		l[api.LabelRole] = role
+		return l
+	}
+
+	s := fakeapi.NewServer(t)
+	s.Add("/apis/kubedb.com/v1alpha1", api.ResourcePluralPostgres, pg)
+	s.Add("/apis/catalog.kubedb.com/v1alpha1", "postgresversions", &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "catalog.kubedb.com/v1alpha1",
+		"kind":       "PostgresVersion",
+		"metadata":   map[string]interface{}{"name": "10.2-v2"},
+		"spec": map[string]interface{}{
+			"version": "10.2",
+			"db":      map[string]interface{}{"image": "kubedb/postgres:10.2-v2"},
+		},
+	}})
+	s.Add("/apis/apps/v1", "statefulsets", &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: testNamespace, UID: "sts-uid", Labels: selector},
+		Spec: apps.StatefulSetSpec{
+			Replicas: int32Ptr(2),
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+		},
+		Status: apps.StatefulSetStatus{Replicas: 2, ReadyReplicas: 2},
+	})
+	s.Add("/api/v1", "pods",
+		&core.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg-0", Namespace: testNamespace, UID: "pg-0-uid", Labels: podLabels("primary")},
+			Status:     core.PodStatus{Phase: core.PodRunning},
+		},
+		&core.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg-1", Namespace: testNamespace, UID: "pg-1-uid", Labels: podLabels("replica")},
+			Status:     core.PodStatus{Phase: core.PodRunning},
+		},
+		&core.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-0",
+				Namespace: testNamespace,
+				Labels:    map[string]string{api.LabelDatabaseKind: api.ResourceKindPostgres, api.LabelDatabaseName: "other"},
+			},
+			Status: core.PodStatus{Phase: core.PodFailed},
+		},
+	)
+	s.Add("/api/v1", "services", &core.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: testNamespace, UID: "svc-uid", Labels: selector},
+		Spec: core.ServiceSpec{
+			Type:      core.ServiceTypeClusterIP,
+			ClusterIP: "10.0.0.10",
+			Ports:     []core.ServicePort{{Name: "api", Port: 5432, Protocol: core.ProtocolTCP}},
+		},
+	})
+	s.Add("/api/v1", "endpoints", &core.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: testNamespace},
+		Subsets: []core.EndpointSubset{{
+			Addresses: []core.EndpointAddress{{IP: "172.17.0.5"}},
+			Ports:     []core.EndpointPort{{Name: "api", Port: 5432}},
+		}},
+	})
+	s.Add("/api/v1", "secrets", &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg-auth", Namespace: testNamespace},
+		Data:       map[string][]byte{"POSTGRES_USER": []byte("postgres"), "POSTGRES_PASSWORD": []byte("secret")},
+	})
+	s.Add("/apis/kubedb.com/v1alpha1", api.ResourcePluralSnapshot, &api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg-snap", Namespace: testNamespace, UID: "snap-uid", Labels: selector},
+		Spec: api.SnapshotSpec{
+			DatabaseName: "pg",
+			Backend:      store.Backend{Local: &store.LocalSpec{MountPath: "/repo"}},
+		},
+		Status: api.SnapshotStatus{Phase: api.SnapshotPhaseSucceeded},
+	})
+	s.Add("/api/v1", "events",
+		&core.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "pg.1", Namespace: testNamespace},
+			InvolvedObject: core.ObjectReference{Kind: api.ResourceKindPostgres, Name: "pg", UID: "pg-uid"},
+			Type:           core.EventTypeNormal,
+			Reason:         "Successful",
+			Message:        "Successfully created Postgres",
+			Source:         core.EventSource{Component: "KubeDB operator"},
+		},
+		&core.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "pg-0.1", Namespace: testNamespace},
+			InvolvedObject: core.ObjectReference{Kind: "Pod", Name: "pg-0", UID: "pg-0-uid"},
+			Type:           core.EventTypeWarning,
+			Reason:         "BackOff",
+			Message:        "Back-off restarting failed container",
+			Source:         core.EventSource{Component: "kubelet"},
+		},
+	)
+	return s
+}
+
+func testDescriber(t *testing.T, s *fakeapi.Server, kind schema.GroupKind) describe.Describer {
+	m, err := describerMap(s.Config())
+	if err != nil {
+		t.Fatalf("describerMap() error = %v", err)
+	}
+	d, ok := m[kind]
+	if !ok {
+		t.Fatalf("no describer for %s", kind)
+	}
+	return d
+}
+
+func TestDescribeObject(t *testing.T) {
+	s := newPostgresServer(t)
+	d := testDescriber(t, s, api.Kind(api.ResourceKindPostgres))
+
+	desc, err := d.(ObjectDescriber).DescribeObject(testNamespace, "pg", describe.DescriberSettings{ShowEvents: true})
+	if err != nil {
+		t.Fatalf("DescribeObject() error = %v", err)
+	}
+	if len(desc.Warnings) > 0 {
+		t.Errorf("Warnings = %v, want none", desc.Warnings)
+	}
+
+	if desc.Kind != api.ResourceKindPostgres || desc.Name != "pg" || desc.Namespace != testNamespace || desc.Status != string(api.DatabasePhaseRunning) {
+		t.Errorf("got %s %s/%s in status %s, want Postgres demo/pg in status Running", desc.Kind, desc.Namespace, desc.Name, desc.Status)
+	}
+	if desc.Spec.StorageType != api.StorageTypeDurable || desc.Spec.Storage == nil || desc.Spec.Storage.Capacity != "1Gi" {
+		t.Errorf("Spec = %+v, want Durable storage of 1Gi", desc.Spec)
+	}
+	if cv := desc.CatalogVersion; cv == nil || cv.Version != "10.2" || cv.DB.Image != "kubedb/postgres:10.2-v2" {
+		t.Errorf("CatalogVersion = %+v, want version 10.2 with image kubedb/postgres:10.2-v2", cv)
+	}
+
+	if len(desc.Workloads) != 1 || desc.Workloads[0].Name != "pg" {
+		t.Fatalf("Workloads = %+v, want StatefulSet pg", desc.Workloads)
+	}
+	// the failed pod of the other database is not counted
+	if pods := desc.Workloads[0].Pods; pods == nil || pods.Running != 2 || pods.Failed != 0 {
+		t.Errorf("Workloads[0].Pods = %+v, want 2 running", pods)
+	}
+	if len(desc.Services) != 1 || desc.Services[0].Ports[0].Endpoints != "172.17.0.5:5432" {
+		t.Errorf("Services = %+v, want pg with endpoint 172.17.0.5:5432", desc.Services)
+	}
+	if len(desc.Secrets) != 1 || desc.Secrets[0].Role != "Database" || desc.Secrets[0].Name != "pg-auth" || desc.Secrets[0].Data["POSTGRES_PASSWORD"] != 6 {
+		t.Errorf("Secrets = %+v, want the Database secret pg-auth", desc.Secrets)
+	}
+	if ha := desc.PostgresHA; ha == nil || ha.Primary != "pg-0" || len(ha.Standby) != 1 || ha.Standby[0] != "pg-1" {
+		t.Errorf("PostgresHA = %+v, want primary pg-0 and standby pg-1", ha)
+	}
+	if len(desc.Snapshots) != 1 || desc.Snapshots[0].Name != "pg-snap" || desc.Snapshots[0].Location != "local:/repo" {
+		t.Errorf("Snapshots = %+v, want pg-snap", desc.Snapshots)
+	}
+	// the events of the pods are only included with deep events
+	if len(desc.Events) != 1 || desc.Events[0].Reason != "Successful" {
+		t.Errorf("Events = %+v, want the Successful event of pg", desc.Events)
+	}
+}
+
+func TestDescribeObjectDeepEvents(t *testing.T) {
+	s := newPostgresServer(t)
+	d := testDescriber(t, s, api.Kind(api.ResourceKindPostgres))
+	d.(EventsDescriber).DeepEvents(true)
+	d.(EventsDescriber).EventsType(core.EventTypeWarning)
+
+	desc, err := d.(ObjectDescriber).DescribeObject(testNamespace, "pg", describe.DescriberSettings{ShowEvents: true})
+	if err != nil {
+		t.Fatalf("DescribeObject() error = %v", err)
+	}
+	if len(desc.Events) != 1 || desc.Events[0].Reason != "BackOff" || desc.Events[0].Object != "Pod/pg-0" {
+		t.Errorf("Events = %+v, want the BackOff warning of Pod/pg-0", desc.Events)
+	}
+}
+
+func TestDescribeQueries(t *testing.T) {
+	s := newPostgresServer(t)
+	d := testDescriber(t, s, api.Kind(api.ResourceKindPostgres))
+	d.(EventsDescriber).DeepEvents(true)
+
+	if _, err := d.Describe(testNamespace, "pg", describe.DescriberSettings{ShowEvents: true}); err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	for _, r := range s.Lists() {
+		if !strings.Contains(r, "Selector=") {
+			t.Errorf("%s lists the whole namespace", r)
+		}
+	}
+	// the pods are used by the workloads, the topology, the HA section and the deep events
+	if pods := s.RequestsFor("/pods?"); len(pods) != 1 {
+		t.Errorf("pods were listed %d times, want once: %v", len(pods), pods)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	s := newPostgresServer(t)
+	d := testDescriber(t, s, api.Kind(api.ResourceKindPostgres))
+
+	out, err := d.Describe(testNamespace, "pg", describe.DescriberSettings{ShowEvents: true})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	for _, want := range []string{
+		"Name:                pg\n",
+		"Replicas:            2  total\n",
+		"Status:              Running\n",
+		"Version:             10.2-v2\n",
+		"  DB Image:          kubedb/postgres:10.2-v2\n",
+		"Pods Status:        2 Running / 0 Waiting / 0 Succeeded / 0 Failed\n",
+		"Database Secret:\n  Name:         pg-auth\n",
+		"High Availability:\n  Primary:  pg-0\n  Standby:  pg-1\n",
+		"  pg-snap  local:/repo",
+		"  Normal  Successful",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Describe() output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Warnings:") {
+		t.Errorf("Describe() output has warnings:\n%s", out)
+	}
+}
+
+func TestDescribeIncomplete(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		s := newPostgresServer(t)
+		s.Fail("secrets", http.StatusForbidden)
+		d := testDescriber(t, s, api.Kind(api.ResourceKindPostgres))
+		d.(StrictDescriber).Strict(strict)
+
+		out, err := d.Describe(testNamespace, "pg", describe.DescriberSettings{})
+		if strict != IsIncompleteDescription(err) {
+			t.Errorf("strict=%v: Describe() error = %v", strict, err)
+		}
+		if !strings.Contains(out, "Warnings:\n  Database Secret pg-auth: ") {
+			t.Errorf("strict=%v: Describe() output does not warn about the secret:\n%s", strict, out)
+		}
+
+		desc, err := d.(ObjectDescriber).DescribeObject(testNamespace, "pg", describe.DescriberSettings{})
+		if strict != IsIncompleteDescription(err) {
+			t.Errorf("strict=%v: DescribeObject() error = %v", strict, err)
+		}
+		if desc == nil || len(desc.Warnings) != 1 {
+			t.Errorf("strict=%v: DescribeObject() = %+v, want a description with one warning", strict, desc)
+		}
+	}
+}
+
+func TestDescribeNotFound(t *testing.T) {
+	s := newPostgresServer(t)
+	d := testDescriber(t, s, api.Kind(api.ResourceKindPostgres))
+
+	if _, err := d.Describe(testNamespace, "missing", describe.DescriberSettings{}); !kerr.IsNotFound(err) {
+		t.Errorf("Describe() error = %v, want NotFound", err)
+	}
+	if _, err := d.(ObjectDescriber).DescribeObject(testNamespace, "missing", describe.DescriberSettings{}); !kerr.IsNotFound(err) {
+		t.Errorf("DescribeObject() error = %v, want NotFound", err)
+	}
+}
+
+// newSnapshotServer serves a snapshot of pg with its storage secret, its backup job and the pod of
+// the job, along with the backup job of another snapshot, and the events of all of them.
+func newSnapshotServer(t *testing.T) *fakeapi.Server {
+	pg := testPostgres()
+	snapshot := &api.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg-snap", Namespace: testNamespace, UID: "snap-uid", Labels: pg.OffshootSelectors()},
+		Spec: api.SnapshotSpec{
+			DatabaseName: "pg",
+			Backend:      store.Backend{StorageSecretName: "gcs-secret", GCS: &store.GCSSpec{Bucket: "backups"}},
+		},
+		Status: api.SnapshotStatus{Phase: api.SnapshotPhaseRunning},
+	}
+	job := func(name string, uid types.UID, owner *api.Snapshot) *batch.Job {
+		job := &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   testNamespace,
+				UID:         uid,
+				Labels:      map[string]string{api.LabelDatabaseKind: api.ResourceKindPostgres},
+				Annotations: map[string]string{api.AnnotationJobType: api.JobTypeBackup},
+			},
+			Spec: batch.JobSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"controller-uid": string(uid)}}},
+		}
+		if owner != nil {
+			job.OwnerReferences = []metav1.OwnerReference{{Kind: api.ResourceKindSnapshot, Name: owner.Name, UID: owner.UID}}
+		}
+		return job
+	}
+	event := func(kind, name string, uid types.UID, reason string) *core.Event {
+		return &core.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name + ".1", Namespace: testNamespace},
+			InvolvedObject: core.ObjectReference{Kind: kind, Name: name, UID: uid},
+			Type:           core.EventTypeNormal,
+			Reason:         reason,
+		}
+	}
+
+	s := fakeapi.NewServer(t)
+	s.Add("/apis/kubedb.com/v1alpha1", api.ResourcePluralSnapshot, snapshot)
+	s.Add("/api/v1", "secrets", &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcs-secret", Namespace: testNamespace},
+		Data:       map[string][]byte{"GOOGLE_PROJECT_ID": []byte("project")},
+	})
+	s.Add("/apis/batch/v1", "jobs",
+		job("kubedb-pg-snap", "job-uid", snapshot),
+		job("kubedb-other-snap", "other-job-uid", &api.Snapshot{ObjectMeta: metav1.ObjectMeta{Name: "other-snap", UID: "other-snap-uid"}}),
+	)
+	s.Add("/api/v1", "pods", &core.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubedb-pg-snap-x", Namespace: testNamespace, UID: "job-pod-uid", Labels: map[string]string{"controller-uid": "job-uid"}},
+	})
+	s.Add("/api/v1", "events",
+		event(api.ResourceKindSnapshot, "pg-snap", "snap-uid", "Starting"),
+		event("Job", "kubedb-pg-snap", "job-uid", "SuccessfulCreate"),
+		event("Pod", "kubedb-pg-snap-x", "job-pod-uid", "Pulled"),
+		event("Job", "kubedb-other-snap", "other-job-uid", "Other"),
+	)
+	return s
+}
+
+func TestDescribeSnapshot(t *testing.T) {
+	for _, deep := range []bool{false, true} {
+		s := newSnapshotServer(t)
+		d := testDescriber(t, s, api.Kind(api.ResourceKindSnapshot))
+		d.(EventsDescriber).DeepEvents(deep)
+
+		desc, err := d.(ObjectDescriber).DescribeObject(testNamespace, "pg-snap", describe.DescriberSettings{ShowEvents: true})
+		if err != nil {
+			t.Fatalf("deep=%v: DescribeObject() error = %v", deep, err)
+		}
+		if len(desc.Secrets) != 1 || desc.Secrets[0].Role != "Storage" || desc.Secrets[0].Name != "gcs-secret" {
+			t.Errorf("deep=%v: Secrets = %+v, want the Storage secret gcs-secret", deep, desc.Secrets)
+		}
+
+		var reasons []string
+		for _, e := range desc.Events {
+			reasons = append(reasons, e.Reason)
+		}
+		sort.Strings(reasons)
+		want := []string{"Starting"}
+		if deep {
+			want = []string{"Pulled", "Starting", "SuccessfulCreate"}
+		}
+		if !reflect.DeepEqual(reasons, want) {
+			t.Errorf("deep=%v: events %v, want %v", deep, reasons, want)
+		}
+
+		out, err := d.Describe(testNamespace, "pg-snap", describe.DescriberSettings{ShowEvents: true})
+		if err != nil {
+			t.Fatalf("deep=%v: Describe() error = %v", deep, err)
+		}
+		if !strings.Contains(out, "Storage Secret:\n  Name:         gcs-secret\n") {
+			t.Errorf("deep=%v: Describe() output is missing the storage secret:\n%s", deep, out)
+		}
+	}
+}
+
+func TestDescribeMongoDBShardTopology(t *testing.T) {
+	node := func(name string) MongoDBNodeDescription {
+		return MongoDBNodeDescription{
+			Name:     name,
+			Replicas: 1,
+			Topology: &TopologyDescription{
+				Title: "Topology",
+				Pods:  []TopologyPodDescription{{Name: name + "-0", Roles: []string{"primary"}, Phase: core.PodRunning}},
+			},
+		}
+	}
+	topology := &MongoDBShardTopologyDescription{
+		Shards:       []MongoDBNodeDescription{node("mg-shard0")},
+		ConfigServer: node("mg-configsvr"),
+		Mongos:       node("mg-mongos"),
+	}
+
+	out, err := tabbedString(func(out io.Writer) error {
+		describeMongoDBShardTopology(topology, versioned.NewPrefixWriter(out))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the topology of every node is nested in the section of the node
+	want := `
+Shard 0:
+  Name:      mg-shard0
+  Replicas:  1  total
+  
+  Topology:
+    Type     Pod          StartTime  Phase
+    ----     ---          ---------  -----
+    primary  mg-shard0-0             Running
+
+Config Server:
+  Name:      mg-configsvr
+  Replicas:  1  total
+  
+  Topology:
+    Type     Pod             StartTime  Phase
+    ----     ---             ---------  -----
+    primary  mg-configsvr-0             Running
+
+Mongos:
+  Name:      mg-mongos
+  Replicas:  1  total
+  
+  Topology:
+    Type     Pod          StartTime  Phase
+    ----     ---          ---------  -----
+    primary  mg-mongos-0             Running
+`
+	if out != want {
+		t.Errorf("describeMongoDBShardTopology() printed\n%s\nwant\n%s", out, want)
+	}
+}
@@ -19,6 +19,7 @@ package describer
 import (
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -90,3 +91,19 @@ func timeToString(t *metav1.Time) string {
 
 	return t.Format(time.RFC1123Z)
 }
+
+// resourceListToString formats a ResourceList as a sorted, comma separated list of name=quantity pairs
+func resourceListToString(rl core.ResourceList) string {
+	names := make([]string, 0, len(rl))
+	for name := range rl {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		q := rl[core.ResourceName(name)]
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, q.String()))
+	}
+	return strings.Join(pairs, ", ")
+}
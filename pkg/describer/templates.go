@@ -0,0 +1,336 @@
+package describer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	ofst "kmodules.xyz/offshoot-api/api/v1"
+)
+
+// PodTemplateDescription holds the pod template of a database, along with its comparison
+// against the workloads created from it.
+type PodTemplateDescription struct {
+	Annotations           map[string]string               `json:"annotations,omitempty"`
+	ControllerAnnotations map[string]string               `json:"controllerAnnotations,omitempty"`
+	Requests              string                          `json:"requests,omitempty"`
+	Limits                string                          `json:"limits,omitempty"`
+	NodeSelector          map[string]string               `json:"nodeSelector,omitempty"`
+	Tolerations           string                          `json:"tolerations,omitempty"`
+	Affinity              string                          `json:"affinity,omitempty"`
+	PriorityClassName     string                          `json:"priorityClassName,omitempty"`
+	Priority              *int32                          `json:"priority,omitempty"`
+	SchedulerName         string                          `json:"schedulerName,omitempty"`
+	ServiceAccountName    string                          `json:"serviceAccountName,omitempty"`
+	Effective             []TemplateComparisonDescription `json:"effective,omitempty"`
+}
+
+// ServiceTemplateDescription holds a service template of a database, along with its comparison
+// against the service created from it.
+type ServiceTemplateDescription struct {
+	Title                    string                                `json:"title"`
+	Service                  string                                `json:"service"`
+	Annotations              map[string]string                     `json:"annotations,omitempty"`
+	Type                     core.ServiceType                      `json:"type,omitempty"`
+	ClusterIP                string                                `json:"clusterIP,omitempty"`
+	ExternalIPs              []string                              `json:"externalIPs,omitempty"`
+	LoadBalancerIP           string                                `json:"loadBalancerIP,omitempty"`
+	LoadBalancerSourceRanges []string                              `json:"loadBalancerSourceRanges,omitempty"`
+	ExternalTrafficPolicy    core.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+	Ports                    string                                `json:"ports,omitempty"`
+	Effective                *TemplateComparisonDescription        `json:"effective,omitempty"`
+}
+
+// TemplateComparisonDescription compares a template against a live object created from it.
+type TemplateComparisonDescription struct {
+	Object string                     `json:"object"`
+	Fields []TemplateFieldDescription `json:"fields,omitempty"`
+}
+
+// TemplateFieldDescription is a single setting of a pod or service template compared against
+// the value found on the live object created from it.
+type TemplateFieldDescription struct {
+	Name      string `json:"name"`
+	Requested string `json:"requested,omitempty"`
+	Effective string `json:"effective,omitempty"`
+	// Diverged is set if the live object does not honour the requested value. Unset template
+	// fields are defaulted by the operator and never count as diverged.
+	Diverged bool `json:"diverged,omitempty"`
+}
+
+// serviceTemplate is a service template of a database and the name of the service created from it.
+type serviceTemplate struct {
+	title    string
+	service  string
+	template ofst.ServiceTemplateSpec
+}
+
+// templateField is a template setting and the value found on the live object.
+type templateField struct {
+	name      string
+	requested string
+	effective string
+}
+
+// compareTemplate lists every field that is either set in the template or found on the object.
+func compareTemplate(object string, fields []templateField) TemplateComparisonDescription {
+	comparison := TemplateComparisonDescription{Object: object}
+	for _, f := range fields {
+		if f.requested == "" && f.effective == "" {
+			continue
+		}
+		comparison.Fields = append(comparison.Fields, TemplateFieldDescription{
+			Name:      f.name,
+			Requested: f.requested,
+			Effective: f.effective,
+			Diverged:  f.requested != "" && f.requested != f.effective,
+		})
+	}
+	return comparison
+}
+
+func getPodTemplate(template *ofst.PodTemplateSpec, statefulSets []apps.StatefulSet, deployments []apps.Deployment) *PodTemplateDescription {
+	if template == nil {
+		return nil
+	}
+	spec := template.Spec
+
+	desc := &PodTemplateDescription{
+		Annotations:           template.Annotations,
+		ControllerAnnotations: template.Controller.Annotations,
+		Requests:              resourceListToString(spec.Resources.Requests),
+		Limits:                resourceListToString(spec.Resources.Limits),
+		NodeSelector:          spec.NodeSelector,
+		Tolerations:           tolerationsToString(spec.Tolerations),
+		Affinity:              affinityToString(spec.Affinity),
+		PriorityClassName:     spec.PriorityClassName,
+		Priority:              spec.Priority,
+		SchedulerName:         spec.SchedulerName,
+		ServiceAccountName:    spec.ServiceAccountName,
+	}
+	for _, s := range statefulSets {
+		desc.Effective = append(desc.Effective, compareTemplate(fmt.Sprintf("StatefulSet %s", s.Name), podTemplateFields(spec, s.Spec.Template.Spec)))
+	}
+	for _, d := range deployments {
+		desc.Effective = append(desc.Effective, compareTemplate(fmt.Sprintf("Deployment %s", d.Name), podTemplateFields(spec, d.Spec.Template.Spec)))
+	}
+	return desc
+}
+
+func podTemplateFields(requested ofst.PodSpec, effective core.PodSpec) []templateField {
+	var resources core.ResourceRequirements
+	if len(effective.Containers) > 0 {
+		resources = effective.Containers[0].Resources
+	}
+	return []templateField{
+		{"Requests", resourceListToString(requested.Resources.Requests), resourceListToString(resources.Requests)},
+		{"Limits", resourceListToString(requested.Resources.Limits), resourceListToString(resources.Limits)},
+		{"Node Selector", selectorToString(requested.NodeSelector), selectorToString(effective.NodeSelector)},
+		{"Tolerations", tolerationsToString(requested.Tolerations), tolerationsToString(effective.Tolerations)},
+		{"Affinity", affinityToString(requested.Affinity), affinityToString(effective.Affinity)},
+		{"Priority Class", requested.PriorityClassName, effective.PriorityClassName},
+		{"Scheduler", requested.SchedulerName, effective.SchedulerName},
+		{"Service Account", requested.ServiceAccountName, effective.ServiceAccountName},
+	}
+}
+
+func getServiceTemplate(cache *objectCache, namespace string, st serviceTemplate, desc *Description) ServiceTemplateDescription {
+	spec := st.template.Spec
+	std := ServiceTemplateDescription{
+		Title:                    st.title,
+		Service:                  st.service,
+		Annotations:              st.template.Annotations,
+		Type:                     spec.Type,
+		ClusterIP:                spec.ClusterIP,
+		ExternalIPs:              spec.ExternalIPs,
+		LoadBalancerIP:           spec.LoadBalancerIP,
+		LoadBalancerSourceRanges: spec.LoadBalancerSourceRanges,
+		ExternalTrafficPolicy:    spec.ExternalTrafficPolicy,
+		Ports:                    templatePortsToString(spec.Ports),
+	}
+
+	svc, err := cache.service(namespace, st.service)
+	if err != nil {
+		desc.warnUnlessNotFound(fmt.Sprintf("Service %s", st.service), err)
+		return std
+	}
+	comparison := compareTemplate(fmt.Sprintf("Service %s", svc.Name), []templateField{
+		{"Type", string(spec.Type), string(svc.Spec.Type)},
+		{"ClusterIP", spec.ClusterIP, svc.Spec.ClusterIP},
+		{"External IPs", strings.Join(spec.ExternalIPs, ","), strings.Join(svc.Spec.ExternalIPs, ",")},
+		{"LoadBalancer IP", spec.LoadBalancerIP, svc.Spec.LoadBalancerIP},
+		{"External Traffic Policy", string(spec.ExternalTrafficPolicy), string(svc.Spec.ExternalTrafficPolicy)},
+		{"Ports", templatePortsToString(spec.Ports), servicePortsToString(spec.Ports, svc.Spec.Ports)},
+	})
+	std.Effective = &comparison
+	return std
+}
+
+func describePodTemplate(desc *PodTemplateDescription, w versioned.PrefixWriter) {
+	if desc == nil {
+		return
+	}
+
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "Pod Template:\n")
+	printAnnotationsMultiline(LEVEL_1, w, "Annotations", desc.Annotations)
+	printAnnotationsMultiline(LEVEL_1, w, "Controller Annotations", desc.ControllerAnnotations)
+	if desc.Requests != "" || desc.Limits != "" {
+		w.Write(LEVEL_1, "Resources:\n")
+		if desc.Requests != "" {
+			w.Write(LEVEL_2, "Requests:\t%s\n", desc.Requests)
+		}
+		if desc.Limits != "" {
+			w.Write(LEVEL_2, "Limits:\t%s\n", desc.Limits)
+		}
+	}
+	if len(desc.NodeSelector) > 0 {
+		w.Write(LEVEL_1, "Node Selector:\t%s\n", labels.FormatLabels(desc.NodeSelector))
+	}
+	if desc.Tolerations != "" {
+		w.Write(LEVEL_1, "Tolerations:\t%s\n", desc.Tolerations)
+	}
+	if desc.Affinity != "" {
+		w.Write(LEVEL_1, "Affinity:\t%s\n", desc.Affinity)
+	}
+	if desc.PriorityClassName != "" {
+		w.Write(LEVEL_1, "Priority Class:\t%s\n", desc.PriorityClassName)
+	}
+	if desc.Priority != nil {
+		w.Write(LEVEL_1, "Priority:\t%d\n", *desc.Priority)
+	}
+	if desc.SchedulerName != "" {
+		w.Write(LEVEL_1, "Scheduler:\t%s\n", desc.SchedulerName)
+	}
+	if desc.ServiceAccountName != "" {
+		w.Write(LEVEL_1, "Service Account:\t%s\n", desc.ServiceAccountName)
+	}
+	for i := range desc.Effective {
+		printTemplateFields(&desc.Effective[i], w)
+	}
+}
+
+func describeServiceTemplate(desc *ServiceTemplateDescription, w versioned.PrefixWriter) {
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "%s:\n", desc.Title)
+	printAnnotationsMultiline(LEVEL_1, w, "Annotations", desc.Annotations)
+	if desc.Type != "" {
+		w.Write(LEVEL_1, "Type:\t%s\n", desc.Type)
+	}
+	if desc.ClusterIP != "" {
+		w.Write(LEVEL_1, "ClusterIP:\t%s\n", desc.ClusterIP)
+	}
+	if len(desc.ExternalIPs) > 0 {
+		w.Write(LEVEL_1, "External IPs:\t%s\n", strings.Join(desc.ExternalIPs, ","))
+	}
+	if desc.LoadBalancerIP != "" {
+		w.Write(LEVEL_1, "LoadBalancer IP:\t%s\n", desc.LoadBalancerIP)
+	}
+	if len(desc.LoadBalancerSourceRanges) > 0 {
+		w.Write(LEVEL_1, "LoadBalancer Source Ranges:\t%s\n", strings.Join(desc.LoadBalancerSourceRanges, ","))
+	}
+	if desc.ExternalTrafficPolicy != "" {
+		w.Write(LEVEL_1, "External Traffic Policy:\t%s\n", desc.ExternalTrafficPolicy)
+	}
+	if desc.Ports != "" {
+		w.Write(LEVEL_1, "Ports:\t%s\n", desc.Ports)
+	}
+	if desc.Effective != nil {
+		printTemplateFields(desc.Effective, w)
+	}
+}
+
+// printTemplateFields prints the requested and effective value of every compared field.
+func printTemplateFields(comparison *TemplateComparisonDescription, w versioned.PrefixWriter) {
+	w.Write(LEVEL_1, "Effective vs. Requested (%s):\n", comparison.Object)
+	w.Write(LEVEL_2, "Field\tRequested\tEffective\tDiverged\n")
+	w.Write(LEVEL_2, "-----\t---------\t---------\t--------\n")
+	for _, f := range comparison.Fields {
+		diverged := ""
+		if f.Diverged {
+			diverged = "yes"
+		}
+		w.Write(LEVEL_2, "%s\t%s\t%s\t%s\n", f.Name, orNone(f.Requested), orNone(f.Effective), orNone(diverged))
+	}
+}
+
+func selectorToString(selector map[string]string) string {
+	if len(selector) == 0 {
+		return ""
+	}
+	return labels.FormatLabels(selector)
+}
+
+func tolerationsToString(tolerations []core.Toleration) string {
+	list := make([]string, 0, len(tolerations))
+	for _, t := range tolerations {
+		s := t.Key
+		if t.Value != "" {
+			s += "=" + t.Value
+		}
+		if t.Effect != "" {
+			s += ":" + string(t.Effect)
+		}
+		if t.Operator == core.TolerationOpExists && t.Key == "" {
+			s = "op=Exists"
+		}
+		list = append(list, s)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+func affinityToString(affinity *core.Affinity) string {
+	if affinity == nil {
+		return ""
+	}
+	var kinds []string
+	if affinity.NodeAffinity != nil {
+		kinds = append(kinds, "NodeAffinity")
+	}
+	if affinity.PodAffinity != nil {
+		kinds = append(kinds, "PodAffinity")
+	}
+	if affinity.PodAntiAffinity != nil {
+		kinds = append(kinds, "PodAntiAffinity")
+	}
+	return strings.Join(kinds, ",")
+}
+
+func templatePortsToString(ports []ofst.ServicePort) string {
+	list := make([]string, 0, len(ports))
+	for _, p := range ports {
+		s := fmt.Sprintf("%s:%d", p.Name, p.Port)
+		if p.NodePort != 0 {
+			s += fmt.Sprintf("/%d", p.NodePort)
+		}
+		list = append(list, s)
+	}
+	return strings.Join(list, ",")
+}
+
+// servicePortsToString formats the live ports matching the ports requested in the template,
+// so that both sides of the comparison list the same ports.
+func servicePortsToString(requested []ofst.ServicePort, ports []core.ServicePort) string {
+	live := make(map[string]core.ServicePort, len(ports))
+	for _, p := range ports {
+		live[p.Name] = p
+	}
+	list := make([]ofst.ServicePort, 0, len(requested))
+	for _, r := range requested {
+		p, ok := live[r.Name]
+		if !ok {
+			continue
+		}
+		port := ofst.ServicePort{Name: p.Name, Port: p.Port}
+		if r.NodePort != 0 {
+			port.NodePort = p.NodePort
+		}
+		list = append(list, port)
+	}
+	return templatePortsToString(list)
+}
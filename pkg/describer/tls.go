@@ -0,0 +1,318 @@
+package describer
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha1"
+)
+
+// certificateExpiryWarning is how long before expiry a certificate is reported as expiring soon.
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+// certificateSecret is a secret holding PEM encoded certificates used by a database.
+// Server certificates found in it are expected to cover the given services. When keys
+// is set, only those keys are parsed. A non-empty unchecked explains why the certificate
+// served by the database can not be verified from this secret.
+type certificateSecret struct {
+	role      string
+	name      string
+	services  []string
+	keys      []string
+	unchecked string
+}
+
+// TLSDescription holds the TLS settings of a database and the certificates found in its secrets.
+type TLSDescription struct {
+	EnableSSL       bool                           `json:"enableSSL,omitempty"`
+	SSLMode         string                         `json:"sslMode,omitempty"`
+	ClusterAuthMode string                         `json:"clusterAuthMode,omitempty"`
+	Secrets         []CertificateSecretDescription `json:"secrets,omitempty"`
+}
+
+// CertificateSecretDescription holds the certificates found in a secret used by a database.
+type CertificateSecretDescription struct {
+	Role   string `json:"role"`
+	Secret string `json:"secret"`
+	// Unchecked explains why the certificate served by the database is not checked, if it is not.
+	Unchecked    string                   `json:"unchecked,omitempty"`
+	Warning      string                   `json:"warning,omitempty"`
+	Certificates []CertificateDescription `json:"certificates,omitempty"`
+}
+
+// CertificateDescription holds the details of a certificate found in a secret used by a database.
+type CertificateDescription struct {
+	Secret      string      `json:"secret"`
+	Key         string      `json:"key"`
+	Subject     string      `json:"subject"`
+	Issuer      string      `json:"issuer"`
+	DNSNames    []string    `json:"dnsNames,omitempty"`
+	IPAddresses []string    `json:"ipAddresses,omitempty"`
+	NotBefore   metav1.Time `json:"notBefore"`
+	NotAfter    metav1.Time `json:"notAfter"`
+	KeyType     string      `json:"keyType"`
+	IsCA        bool        `json:"isCA,omitempty"`
+	Warnings    []string    `json:"warnings,omitempty"`
+}
+
+// getCertificates parses every PEM encoded certificate found in the secret. Keys that do not
+// hold certificates, such as private keys or keystores, are skipped.
+func getCertificates(cache *objectCache, namespace string, cs certificateSecret, now time.Time) ([]CertificateDescription, error) {
+	secret, err := cache.secret(namespace, cs.name)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := cs.keys
+	if keys == nil {
+		keys = make([]string, 0, len(secret.Data))
+		for k := range secret.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+
+	var certs []CertificateDescription
+	for _, key := range keys {
+		rest := secret.Data[key]
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				continue
+			}
+			certs = append(certs, describeCertificate(cs, key, cert, namespace, now))
+		}
+	}
+	return certs, nil
+}
+
+func describeCertificate(cs certificateSecret, key string, cert *x509.Certificate, namespace string, now time.Time) CertificateDescription {
+	desc := CertificateDescription{
+		Secret:    cs.name,
+		Key:       key,
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		DNSNames:  cert.DNSNames,
+		NotBefore: metav1.NewTime(cert.NotBefore),
+		NotAfter:  metav1.NewTime(cert.NotAfter),
+		KeyType:   publicKeyType(cert),
+		IsCA:      cert.IsCA,
+	}
+	for _, ip := range cert.IPAddresses {
+		desc.IPAddresses = append(desc.IPAddresses, ip.String())
+	}
+
+	switch {
+	case now.After(cert.NotAfter):
+		desc.Warnings = append(desc.Warnings, fmt.Sprintf("certificate expired on %s", cert.NotAfter.Format(time.RFC1123Z)))
+	case cert.NotAfter.Sub(now) < certificateExpiryWarning:
+		desc.Warnings = append(desc.Warnings, fmt.Sprintf("certificate expires within 30 days, on %s", cert.NotAfter.Format(time.RFC1123Z)))
+	case now.Before(cert.NotBefore):
+		desc.Warnings = append(desc.Warnings, fmt.Sprintf("certificate is not valid before %s", cert.NotBefore.Format(time.RFC1123Z)))
+	}
+
+	if !cert.IsCA && isServerCertificate(cert) {
+		for _, svc := range cs.services {
+			if !coversService(cert, svc, namespace) {
+				desc.Warnings = append(desc.Warnings, fmt.Sprintf("SANs do not cover service %s", svc))
+			}
+		}
+	}
+	return desc
+}
+
+func isServerCertificate(cert *x509.Certificate) bool {
+	if len(cert.ExtKeyUsage) == 0 {
+		return true
+	}
+	for _, usage := range cert.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageServerAuth || usage == x509.ExtKeyUsageAny {
+			return true
+		}
+	}
+	return false
+}
+
+// coversService reports whether the certificate is valid for any of the in-cluster DNS names of the service.
+func coversService(cert *x509.Certificate, service, namespace string) bool {
+	for _, host := range []string{
+		service,
+		fmt.Sprintf("%s.%s", service, namespace),
+		fmt.Sprintf("%s.%s.svc", service, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace),
+	} {
+		if cert.VerifyHostname(host) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func publicKeyType(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d bits", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", key.Curve.Params().Name)
+	}
+	return cert.PublicKeyAlgorithm.String()
+}
+
+// getCertificateSecrets reads the certificates of every secret in secrets. A secret that can not be read
+// is reported with a warning instead of its certificates.
+func getCertificateSecrets(cache *objectCache, namespace string, secrets []certificateSecret, desc *Description) []CertificateSecretDescription {
+	now := time.Now()
+	var list []CertificateSecretDescription
+	for _, cs := range secrets {
+		if cs.name == "" {
+			continue
+		}
+		csd := CertificateSecretDescription{
+			Role:      cs.role,
+			Secret:    cs.name,
+			Unchecked: cs.unchecked,
+		}
+		certs, err := getCertificates(cache, namespace, cs, now)
+		if err != nil {
+			desc.warn(fmt.Sprintf("%s certificate Secret %s", cs.role, cs.name), err)
+			csd.Warning = fmt.Sprintf("failed to read secret %q: %v", cs.name, err)
+		}
+		csd.Certificates = certs
+		list = append(list, csd)
+	}
+	return list
+}
+
+func describeTLS(desc *TLSDescription, w versioned.PrefixWriter) {
+	if desc == nil {
+		return
+	}
+
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "TLS:\n")
+	if desc.EnableSSL {
+		w.Write(LEVEL_1, "EnableSSL:\t%v\n", desc.EnableSSL)
+	}
+	if desc.SSLMode != "" {
+		w.Write(LEVEL_1, "SSLMode:\t%s\n", desc.SSLMode)
+	}
+	if desc.ClusterAuthMode != "" {
+		w.Write(LEVEL_1, "ClusterAuthMode:\t%s\n", desc.ClusterAuthMode)
+	}
+	for _, cs := range desc.Secrets {
+		w.Write(LEVEL_1, "%s Certificates:\t%s\n", cs.Role, cs.Secret)
+		if cs.Unchecked != "" {
+			w.Write(LEVEL_2, "Server Certificate:\tnot checked, %s\n", cs.Unchecked)
+		}
+		if cs.Warning != "" {
+			w.Write(LEVEL_2, "WARNING:\t%s\n", cs.Warning)
+			continue
+		}
+		if len(cs.Certificates) == 0 {
+			w.Write(LEVEL_2, "No certificates.\n")
+			continue
+		}
+		for _, c := range cs.Certificates {
+			w.Write(LEVEL_2, "%s:\n", c.Key)
+			w.Write(LEVEL_3, "Subject:\t%s\n", c.Subject)
+			w.Write(LEVEL_3, "Issuer:\t%s\n", c.Issuer)
+			if c.IsCA {
+				w.Write(LEVEL_3, "CA:\ttrue\n")
+			}
+			if sans := append(append([]string{}, c.DNSNames...), c.IPAddresses...); len(sans) > 0 {
+				w.Write(LEVEL_3, "SANs:\t%s\n", strings.Join(sans, ", "))
+			}
+			w.Write(LEVEL_3, "Validity:\t%s - %s\n", timeToString(&c.NotBefore), timeToString(&c.NotAfter))
+			w.Write(LEVEL_3, "Key Type:\t%s\n", c.KeyType)
+			for _, warning := range c.Warnings {
+				w.Write(LEVEL_3, "WARNING:\t%s\n", warning)
+			}
+		}
+	}
+}
+
+func mongoDBCertificateSecrets(item *api.MongoDB) []certificateSecret {
+	if item.Spec.CertificateSecret == nil {
+		return nil
+	}
+	// The secret only holds the CA; the server certificate is issued inside each pod.
+	return []certificateSecret{
+		{
+			role:      "CA",
+			name:      item.Spec.CertificateSecret.SecretName,
+			keys:      []string{"ca.cert"},
+			unchecked: "it is issued by the CA inside each pod",
+		},
+	}
+}
+
+func elasticsearchCertificateSecrets(item *api.Elasticsearch) []certificateSecret {
+	if !item.Spec.EnableSSL || item.Spec.CertificateSecret == nil {
+		return nil
+	}
+	// The node certificate lives in the node.jks keystore; only the root CA is PEM encoded.
+	return []certificateSecret{
+		{
+			role:      "CA",
+			name:      item.Spec.CertificateSecret.SecretName,
+			keys:      []string{"root.pem"},
+			unchecked: "it is stored in the node.jks keystore",
+		},
+	}
+}
+
+func etcdCertificateSecrets(item *api.Etcd) []certificateSecret {
+	tls := item.Spec.TLS
+	if tls == nil {
+		return nil
+	}
+	var secrets []certificateSecret
+	if tls.Member != nil {
+		secrets = append(secrets,
+			certificateSecret{role: "Peer", name: tls.Member.PeerSecret, services: []string{item.PeerServiceName()}},
+			certificateSecret{role: "Server", name: tls.Member.ServerSecret, services: []string{item.ClientServiceName()}},
+		)
+	}
+	return append(secrets, certificateSecret{role: "Operator", name: tls.OperatorSecret})
+}
+
+func mongoDBTLS(item *api.MongoDB) *TLSDescription {
+	sslEnabled := item.Spec.SSLMode != "" && item.Spec.SSLMode != api.SSLModeDisabled
+	if !sslEnabled && item.Spec.CertificateSecret == nil {
+		return nil
+	}
+	return &TLSDescription{
+		SSLMode:         string(item.Spec.SSLMode),
+		ClusterAuthMode: string(item.Spec.ClusterAuthMode),
+	}
+}
+
+func elasticsearchTLS(item *api.Elasticsearch) *TLSDescription {
+	if !item.Spec.EnableSSL {
+		return nil
+	}
+	return &TLSDescription{EnableSSL: true}
+}
+
+func etcdTLS(item *api.Etcd) *TLSDescription {
+	if item.Spec.TLS == nil {
+		return nil
+	}
+	return &TLSDescription{}
+}
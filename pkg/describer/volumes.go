@@ -0,0 +1,90 @@
+package describer
+
+import (
+	"sort"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+)
+
+// VolumeClaimDescription holds the observed state of a PVC backing a database.
+type VolumeClaimDescription struct {
+	Name         string                          `json:"name"`
+	Phase        core.PersistentVolumeClaimPhase `json:"phase"`
+	Volume       string                          `json:"volume,omitempty"`
+	Capacity     string                          `json:"capacity,omitempty"`
+	StorageClass string                          `json:"storageClass,omitempty"`
+	AccessModes  string                          `json:"accessModes,omitempty"`
+	Warning      string                          `json:"warning,omitempty"`
+}
+
+// getVolumeClaims lists the PVCs matching selector and compares them against the requested pvcSpec.
+// Claims that are still Pending or whose capacity differs from the requested one carry a warning.
+func getVolumeClaims(cache *objectCache, namespace string, selector labels.Selector, pvcSpec *core.PersistentVolumeClaimSpec) ([]VolumeClaimDescription, error) {
+	pvcs, err := cache.persistentVolumeClaims(namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pvcs, func(i, j int) bool { return pvcs[i].Name < pvcs[j].Name })
+
+	var claims []VolumeClaimDescription
+	for _, pvc := range pvcs {
+		claim := VolumeClaimDescription{
+			Name:        pvc.Name,
+			Phase:       pvc.Status.Phase,
+			Volume:      pvc.Spec.VolumeName,
+			AccessModes: getAccessModesAsString(pvc.Status.AccessModes),
+		}
+		if pvc.Spec.StorageClassName != nil {
+			claim.StorageClass = *pvc.Spec.StorageClassName
+		}
+		capacity, bound := pvc.Status.Capacity[core.ResourceStorage]
+		if bound {
+			claim.Capacity = capacity.String()
+		}
+
+		switch {
+		case pvc.Status.Phase == core.ClaimPending:
+			claim.Warning = "claim is pending"
+		case pvc.Status.Phase == core.ClaimLost:
+			claim.Warning = "bound volume is lost"
+		case bound && pvcSpec != nil:
+			if requested, ok := pvcSpec.Resources.Requests[core.ResourceStorage]; ok && requested.Cmp(capacity) != 0 {
+				claim.Warning = "capacity differs from requested " + requested.String()
+			}
+		}
+		claims = append(claims, claim)
+	}
+	return claims, nil
+}
+
+func describeVolumeClaims(claims []VolumeClaimDescription, w versioned.PrefixWriter) {
+	if len(claims) == 0 {
+		return
+	}
+
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "PersistentVolumeClaims:\n")
+	w.Write(LEVEL_1, "Name\tStatus\tVolume\tCapacity\tAccess Modes\tStorageClass\tWarning\n")
+	w.Write(LEVEL_1, "----\t------\t------\t--------\t------------\t------------\t-------\n")
+	for _, c := range claims {
+		w.Write(LEVEL_1, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			c.Name,
+			c.Phase,
+			orNone(c.Volume),
+			orNone(c.Capacity),
+			orNone(c.AccessModes),
+			orNone(c.StorageClass),
+			orNone(c.Warning),
+		)
+	}
+	w.Flush()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
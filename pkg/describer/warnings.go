@@ -0,0 +1,76 @@
+package describer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+)
+
+// StrictDescriber is implemented by the describers that report the parts of a description
+// they failed to collect.
+type StrictDescriber interface {
+	// Strict makes Describe return an IncompleteDescriptionError, along with the description,
+	// if any part of it could not be collected.
+	Strict(strict bool)
+}
+
+// IncompleteDescriptionError is returned in strict mode when some parts of a description could not be collected.
+type IncompleteDescriptionError struct {
+	Warnings []string
+}
+
+func (e *IncompleteDescriptionError) Error() string {
+	return fmt.Sprintf("description is incomplete: %s", strings.Join(e.Warnings, "; "))
+}
+
+// IsIncompleteDescription reports whether err is an IncompleteDescriptionError.
+func IsIncompleteDescription(err error) bool {
+	_, ok := err.(*IncompleteDescriptionError)
+	return ok
+}
+
+// warn records that the named part of the description could not be collected.
+func (d *Description) warn(what string, err error) {
+	d.Warnings = append(d.Warnings, fmt.Sprintf("%s: %v", what, err))
+}
+
+// warnUnlessNotFound is warn for lookups of optional objects, whose absence is not a failure.
+func (d *Description) warnUnlessNotFound(what string, err error) {
+	if !kerr.IsNotFound(err) {
+		d.warn(what, err)
+	}
+}
+
+// describeWithWarnings is tabbedString for the KubeDB describers. The Warnings section listing every
+// failed sub-query of desc is appended to the description. In strict mode, an IncompleteDescriptionError
+// is returned along with the description if there were any.
+func describeWithWarnings(strict bool, desc *Description, f func(w versioned.PrefixWriter)) (string, error) {
+	s, err := tabbedString(func(out io.Writer) error {
+		w := versioned.NewPrefixWriter(out)
+		f(w)
+		if len(desc.Warnings) > 0 {
+			w.Write(LEVEL_0, "\n")
+			w.Write(LEVEL_0, "Warnings:\n")
+			for _, warning := range desc.Warnings {
+				w.Write(LEVEL_1, "%s\n", warning)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return s, err
+	}
+	return s, incompleteDescription(strict, desc)
+}
+
+// incompleteDescription returns an IncompleteDescriptionError in strict mode if any part of desc
+// could not be collected.
+func incompleteDescription(strict bool, desc *Description) error {
+	if strict && len(desc.Warnings) > 0 {
+		return &IncompleteDescriptionError{Warnings: desc.Warnings}
+	}
+	return nil
+}
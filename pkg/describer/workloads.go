@@ -0,0 +1,360 @@
+package describer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/kubectl/describe/versioned"
+	meta_util "kmodules.xyz/client-go/meta"
+)
+
+type WorkloadDescription struct {
+	Kind              string            `json:"kind"`
+	Name              string            `json:"name"`
+	CreationTimestamp metav1.Time       `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	Desired           int32             `json:"desired"`
+	Current           int32             `json:"current"`
+	// Updated, Available and Unavailable are only reported for Deployments.
+	Updated     int32 `json:"updated,omitempty"`
+	Available   int32 `json:"available,omitempty"`
+	Unavailable int32 `json:"unavailable,omitempty"`
+	// Pods is nil if the pods of the workload could not be listed.
+	Pods *PodStatusDescription `json:"pods,omitempty"`
+}
+
+// PodStatusDescription counts the pods of a workload by phase.
+type PodStatusDescription struct {
+	Running   int `json:"running"`
+	Waiting   int `json:"waiting"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+type ServiceDescription struct {
+	Name           string                   `json:"name"`
+	Labels         map[string]string        `json:"labels,omitempty"`
+	Annotations    map[string]string        `json:"annotations,omitempty"`
+	Type           core.ServiceType         `json:"type"`
+	ClusterIP      string                   `json:"clusterIP,omitempty"`
+	ExternalIPs    []string                 `json:"externalIPs,omitempty"`
+	LoadBalancerIP string                   `json:"loadBalancerIP,omitempty"`
+	ExternalName   string                   `json:"externalName,omitempty"`
+	Ingress        string                   `json:"ingress,omitempty"`
+	Ports          []ServicePortDescription `json:"ports,omitempty"`
+}
+
+type ServicePortDescription struct {
+	Name       string             `json:"name,omitempty"`
+	Port       int32              `json:"port"`
+	Protocol   core.Protocol      `json:"protocol"`
+	TargetPort intstr.IntOrString `json:"targetPort"`
+	NodePort   int32              `json:"nodePort,omitempty"`
+	Endpoints  string             `json:"endpoints"`
+}
+
+// SecretDescription holds the size of every key of a secret used by a database. Secret values are never included.
+type SecretDescription struct {
+	Role        string            `json:"role"`
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Type        core.SecretType   `json:"type"`
+	Data        map[string]int    `json:"data,omitempty"`
+}
+
+// TopologyDescription lists the pods of a database, or of a part of it, along with the roles they hold.
+type TopologyDescription struct {
+	Title string                   `json:"title"`
+	Pods  []TopologyPodDescription `json:"pods"`
+}
+
+type TopologyPodDescription struct {
+	Name      string        `json:"name"`
+	Roles     []string      `json:"roles,omitempty"`
+	StartTime *metav1.Time  `json:"startTime,omitempty"`
+	Phase     core.PodPhase `json:"phase"`
+}
+
+func getPodStatus(cache *objectCache, namespace string, selector labels.Selector) (*PodStatusDescription, error) {
+	running, waiting, succeeded, failed, err := getPodStatusForController(cache, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	return &PodStatusDescription{Running: running, Waiting: waiting, Succeeded: succeeded, Failed: failed}, nil
+}
+
+func getWorkloads(cache *objectCache, namespace string, statefulSets []apps.StatefulSet, deployments []apps.Deployment, desc *Description) []WorkloadDescription {
+	var workloads []WorkloadDescription
+	podStatus := func(kind, name string, ls *metav1.LabelSelector) *PodStatusDescription {
+		selector, err := metav1.LabelSelectorAsSelector(ls)
+		if err != nil {
+			desc.warn(fmt.Sprintf("selector of %s %s", kind, name), err)
+			return nil
+		}
+		pods, err := getPodStatus(cache, namespace, selector)
+		if err != nil {
+			desc.warn(fmt.Sprintf("Pods of %s %s", kind, name), err)
+		}
+		return pods
+	}
+
+	for _, s := range statefulSets {
+		var desired int32
+		if s.Spec.Replicas != nil {
+			desired = *s.Spec.Replicas
+		}
+		workloads = append(workloads, WorkloadDescription{
+			Kind:              "StatefulSet",
+			Name:              s.Name,
+			CreationTimestamp: s.CreationTimestamp,
+			Labels:            s.Labels,
+			Annotations:       s.Annotations,
+			Desired:           desired,
+			Current:           s.Status.Replicas,
+			Pods:              podStatus("StatefulSet", s.Name, s.Spec.Selector),
+		})
+	}
+	for _, d := range deployments {
+		var desired int32
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		workloads = append(workloads, WorkloadDescription{
+			Kind:              "Deployment",
+			Name:              d.Name,
+			CreationTimestamp: d.CreationTimestamp,
+			Labels:            d.Labels,
+			Annotations:       d.Annotations,
+			Desired:           desired,
+			Current:           d.Status.Replicas,
+			Updated:           d.Status.UpdatedReplicas,
+			Available:         d.Status.AvailableReplicas,
+			Unavailable:       d.Status.UnavailableReplicas,
+			Pods:              podStatus("Deployment", d.Name, d.Spec.Selector),
+		})
+	}
+	return workloads
+}
+
+func getServices(cache *objectCache, namespace string, selector labels.Selector, desc *Description) []ServiceDescription {
+	services, err := cache.services(namespace, selector)
+	if err != nil {
+		desc.warn("Services", err)
+		return nil
+	}
+
+	var list []ServiceDescription
+	for _, s := range services {
+		endpoints, err := cache.endpoints(namespace, s.Name)
+		if err != nil {
+			desc.warnUnlessNotFound(fmt.Sprintf("Endpoints %s", s.Name), err)
+			endpoints = &core.Endpoints{}
+		}
+		svc := ServiceDescription{
+			Name:           s.Name,
+			Labels:         s.Labels,
+			Annotations:    s.Annotations,
+			Type:           s.Spec.Type,
+			ClusterIP:      s.Spec.ClusterIP,
+			ExternalIPs:    s.Spec.ExternalIPs,
+			LoadBalancerIP: s.Spec.LoadBalancerIP,
+			ExternalName:   s.Spec.ExternalName,
+			Ingress:        buildIngressString(s.Status.LoadBalancer.Ingress),
+		}
+		for _, sp := range s.Spec.Ports {
+			svc.Ports = append(svc.Ports, ServicePortDescription{
+				Name:       sp.Name,
+				Port:       sp.Port,
+				Protocol:   sp.Protocol,
+				TargetPort: sp.TargetPort,
+				NodePort:   sp.NodePort,
+				Endpoints:  formatEndpoints(endpoints, sets.NewString(sp.Name)),
+			})
+		}
+		list = append(list, svc)
+	}
+	return list
+}
+
+// getSecrets reads the referenced secrets, ordered by their role in the database.
+func getSecrets(cache *objectCache, namespace string, secretVolumes map[string]*core.SecretVolumeSource, desc *Description) []SecretDescription {
+	roles := make([]string, 0, len(secretVolumes))
+	for role := range secretVolumes {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	skipAnnotations := sets.NewString(meta_util.LastAppliedConfigAnnotation)
+	var secrets []SecretDescription
+	for _, role := range roles {
+		name := secretVolumes[role].SecretName
+		secret, err := cache.secret(namespace, name)
+		if err != nil {
+			desc.warn(fmt.Sprintf("%s Secret %s", role, name), err)
+			continue
+		}
+		sd := SecretDescription{
+			Role:   role,
+			Name:   secret.Name,
+			Labels: secret.Labels,
+			Type:   secret.Type,
+			Data:   make(map[string]int, len(secret.Data)),
+		}
+		for k, v := range secret.Annotations {
+			if !skipAnnotations.Has(k) {
+				if sd.Annotations == nil {
+					sd.Annotations = map[string]string{}
+				}
+				sd.Annotations[k] = v
+			}
+		}
+		for k, v := range secret.Data {
+			sd.Data[k] = len(v)
+		}
+		secrets = append(secrets, sd)
+	}
+	return secrets
+}
+
+// getTopology lists the pods matching selector, tagging each pod with the keys of the
+// specific selectors it matches.
+func getTopology(cache *objectCache, namespace, title string, selector labels.Selector, specific map[string]labels.Selector, desc *Description) *TopologyDescription {
+	pods, err := cache.pods(namespace, selector)
+	if err != nil {
+		desc.warn(fmt.Sprintf("Pods of %s", title), err)
+		return nil
+	}
+	return &TopologyDescription{
+		Title: title,
+		Pods:  topologyPods(pods, specific),
+	}
+}
+
+func topologyPods(pods []core.Pod, specific map[string]labels.Selector) []TopologyPodDescription {
+	list := make([]TopologyPodDescription, 0, len(pods))
+	for _, pod := range pods {
+		roles := make([]string, 0)
+		for key, val := range specific {
+			if val.Matches(labels.Set(pod.Labels)) {
+				roles = append(roles, key)
+			}
+		}
+		sort.Strings(roles)
+		list = append(list, TopologyPodDescription{
+			Name:      pod.Name,
+			Roles:     roles,
+			StartTime: pod.Status.StartTime,
+			Phase:     pod.Status.Phase,
+		})
+	}
+	return list
+}
+
+func describeWorkloads(workloads []WorkloadDescription, w versioned.PrefixWriter) {
+	for _, wd := range workloads {
+		w.Write(LEVEL_0, "\n")
+		w.Write(LEVEL_0, "%s:\t\n", wd.Kind)
+		w.Write(LEVEL_1, "Name:\t%s\n", wd.Name)
+		w.Write(LEVEL_1, "CreationTimestamp:\t%s\n", timeToString(&wd.CreationTimestamp))
+		printLabelsMultiline(LEVEL_1, w, "Labels", wd.Labels)
+		printAnnotationsMultiline(LEVEL_1, w, "Annotations", wd.Annotations)
+		if wd.Kind == "Deployment" {
+			w.Write(LEVEL_1, "Replicas:\t%d desired | %d updated | %d total | %d available | %d unavailable\n", wd.Desired, wd.Updated, wd.Current, wd.Available, wd.Unavailable)
+		} else {
+			w.Write(LEVEL_1, "Replicas:\t%d desired | %d total\n", wd.Desired, wd.Current)
+		}
+		if p := wd.Pods; p != nil {
+			w.Write(LEVEL_1, "Pods Status:\t%d Running / %d Waiting / %d Succeeded / %d Failed\n", p.Running, p.Waiting, p.Succeeded, p.Failed)
+		}
+	}
+}
+
+func describeServices(services []ServiceDescription, w versioned.PrefixWriter) {
+	for _, svc := range services {
+		w.Write(LEVEL_0, "\n")
+		w.Write(LEVEL_0, "Service:\t\n")
+		w.Write(LEVEL_1, "Name:\t%s\n", svc.Name)
+		printLabelsMultiline(LEVEL_1, w, "Labels", svc.Labels)
+		printAnnotationsMultiline(LEVEL_1, w, "Annotations", svc.Annotations)
+		w.Write(LEVEL_1, "Type:\t%s\n", svc.Type)
+		w.Write(LEVEL_1, "IP:\t%s\n", svc.ClusterIP)
+		if len(svc.ExternalIPs) > 0 {
+			w.Write(LEVEL_1, "External IPs:\t%v\n", strings.Join(svc.ExternalIPs, ","))
+		}
+		if svc.LoadBalancerIP != "" {
+			w.Write(LEVEL_1, "IP:\t%s\n", svc.LoadBalancerIP)
+		}
+		if svc.ExternalName != "" {
+			w.Write(LEVEL_1, "External Name:\t%s\n", svc.ExternalName)
+		}
+		if svc.Ingress != "" {
+			w.Write(LEVEL_1, "LoadBalancer Ingress:\t%s\n", svc.Ingress)
+		}
+		for _, sp := range svc.Ports {
+			name := sp.Name
+			if name == "" {
+				name = "<unset>"
+			}
+			w.Write(LEVEL_1, "Port:\t%s\t%d/%s\n", name, sp.Port, sp.Protocol)
+			w.Write(LEVEL_1, "TargetPort:\t%s/%s\n", sp.TargetPort.String(), sp.Protocol)
+			if sp.NodePort != 0 {
+				w.Write(LEVEL_1, "NodePort:\t%s\t%d/%s\n", name, sp.NodePort, sp.Protocol)
+			}
+			w.Write(LEVEL_1, "Endpoints:\t%s\n", sp.Endpoints)
+		}
+	}
+}
+
+func describeSecrets(secrets []SecretDescription, w versioned.PrefixWriter) {
+	for _, secret := range secrets {
+		w.Write(LEVEL_0, "\n")
+		w.Write(LEVEL_0, "%s Secret:\n", secret.Role)
+		w.Write(LEVEL_1, "Name:\t%s\n", secret.Name)
+		printLabelsMultiline(LEVEL_1, w, "Labels", secret.Labels)
+		printAnnotationsMultiline(LEVEL_1, w, "Annotations", secret.Annotations)
+
+		w.Write(LEVEL_1, "\nType:\t%s\n", secret.Type)
+
+		w.Write(LEVEL_1, "\nData\n====\n")
+		keys := make([]string, 0, len(secret.Data))
+		for k := range secret.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			w.Write(LEVEL_1, "%s:\t%d bytes\n", k, secret.Data[k])
+		}
+	}
+}
+
+func describeTopology(level int, topology *TopologyDescription, w versioned.PrefixWriter) {
+	if topology == nil {
+		return
+	}
+	w.Write(level, "\n")
+	w.Write(level, "%s:\n", topology.Title)
+	w.Write(level+1, "Type\tPod\tStartTime\tPhase\n")
+	w.Write(level+1, "----\t---\t---------\t-----\n")
+	describeTopologyPods(level+1, topology.Pods, w)
+}
+
+func describeTopologyPods(level int, pods []TopologyPodDescription, w versioned.PrefixWriter) {
+	for _, pod := range pods {
+		w.Write(level, "%s\t%s\t%s\t%s\n",
+			strings.Join(pod.Roles, "|"),
+			pod.Name,
+			timeToString(pod.StartTime),
+			pod.Phase,
+		)
+	}
+	w.Flush()
+}
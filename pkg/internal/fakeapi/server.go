@@ -0,0 +1,186 @@
+// Package fakeapi serves Kubernetes objects over HTTP for tests, so that code is tested with the
+// clientsets it uses in production.
+package fakeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// Server serves the objects added to it over the read-only Kubernetes API. Lists honor label and field selectors.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// objects are keyed by the API path of their resource, i.e. /api/v1/pods.
+	objects map[string][]runtime.Object
+	// failures holds the status code returned for all requests of a resource.
+	failures map[string]int
+	// requests are the request URIs received, in order, lists are the ones of list requests.
+	requests []string
+	lists    []string
+}
+
+// NewServer starts a Server, which is closed when the test completes.
+func NewServer(t *testing.T) *Server {
+	s := &Server{
+		objects:  map[string][]runtime.Object{},
+		failures: map[string]int{},
+	}
+	s.Server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Config returns the client config of the server.
+func (s *Server) Config() *rest.Config {
+	return &rest.Config{Host: s.URL}
+}
+
+// Add serves objs as the given resource of the API group version at path, i.e. /api/v1 or /apis/apps/v1.
+func (s *Server) Add(path, resource string, objs ...runtime.Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[path+"/"+resource] = append(s.objects[path+"/"+resource], objs...)
+}
+
+// Fail makes all requests of resource fail with code.
+func (s *Server) Fail(resource string, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[resource] = code
+}
+
+// RequestsFor returns the received requests whose URI contains the given path.
+func (s *Server) RequestsFor(path string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []string
+	for _, r := range s.requests {
+		if strings.Contains(r, path) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+
+	// /api/v1/... or /apis/<group>/<version>/...
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	n := 2
+	if parts[0] == "apis" {
+		n = 3
+	}
+	if len(parts) <= n {
+		writeStatus(w, kerr.NewNotFound(schema.GroupResource{}, r.URL.Path))
+		return
+	}
+	path := "/" + strings.Join(parts[:n], "/")
+	parts = parts[n:]
+	var namespace, name string
+	if len(parts) > 2 && parts[0] == "namespaces" {
+		namespace, parts = parts[1], parts[2:]
+	}
+	resource := parts[0]
+	if len(parts) > 1 {
+		name = parts[1]
+	}
+
+	if r.Method != http.MethodGet {
+		writeStatus(w, kerr.NewMethodNotSupported(schema.GroupResource{Resource: resource}, r.Method))
+		return
+	}
+	if code, ok := s.failures[resource]; ok {
+		writeStatus(w, kerr.NewGenericServerResponse(code, r.Method, schema.GroupResource{Resource: resource}, name, "injected failure", 0, false))
+		return
+	}
+
+	if name != "" {
+		for _, obj := range s.objects[path+"/"+resource] {
+			m, _ := meta.Accessor(obj)
+			if m.GetNamespace() == namespace && m.GetName() == name {
+				writeJSON(w, http.StatusOK, obj)
+				return
+			}
+		}
+		writeStatus(w, kerr.NewNotFound(schema.GroupResource{Resource: resource}, name))
+		return
+	}
+
+	s.lists = append(s.lists, r.URL.RequestURI())
+	labelSelector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, kerr.NewBadRequest(err.Error()))
+		return
+	}
+	fieldSelector, err := fields.ParseSelector(r.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		writeStatus(w, kerr.NewBadRequest(err.Error()))
+		return
+	}
+	items := []runtime.Object{}
+	for _, obj := range s.objects[path+"/"+resource] {
+		m, _ := meta.Accessor(obj)
+		if namespace != "" && m.GetNamespace() != namespace {
+			continue
+		}
+		if labelSelector.Matches(labels.Set(m.GetLabels())) && fieldSelector.Matches(objectFields(obj, m)) {
+			items = append(items, obj)
+		}
+	}
+	// the kind of the list is left to the client, which decodes into the list type it expects
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"metadata": map[string]interface{}{},
+		"items":    items,
+	})
+}
+
+// Lists returns the URIs of the list requests received, in order.
+func (s *Server) Lists() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lists...)
+}
+
+// objectFields returns the fields objects are selected by.
+func objectFields(obj runtime.Object, m metav1.Object) fields.Set {
+	set := fields.Set{
+		"metadata.name":      m.GetName(),
+		"metadata.namespace": m.GetNamespace(),
+	}
+	if e, ok := obj.(*core.Event); ok {
+		set["involvedObject.uid"] = string(e.InvolvedObject.UID)
+	}
+	return set
+}
+
+func writeStatus(w http.ResponseWriter, err *kerr.StatusError) {
+	status := err.ErrStatus
+	status.Kind = "Status"
+	status.APIVersion = "v1"
+	writeJSON(w, int(status.Code), &status)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}